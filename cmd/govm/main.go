@@ -1,9 +1,22 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"github.com/wenzzy/govm/internal/cli"
+	"github.com/wenzzy/govm/internal/shim"
 )
 
 func main() {
+	// Invoked through a shim like ~/.govm/bin/go: run the real tool
+	if shim.IsShim(os.Args[0]) {
+		if err := shim.Run(filepath.Base(os.Args[0]), os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "govm: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	cli.Execute()
 }
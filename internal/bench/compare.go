@@ -0,0 +1,205 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+)
+
+// Delta is the change of a benchmark relative to the first result set
+type Delta struct {
+	Percent     float64
+	P           float64 // Mann-Whitney U p-value, 0 for the geomean
+	Significant bool
+	Valid       bool // False when either side has no samples
+}
+
+// String formats the delta like benchstat: the change when it is
+// significant, "~" otherwise
+func (d Delta) String() string {
+	switch {
+	case !d.Valid:
+		return ""
+	case d.P == 0 && d.Significant:
+		return fmt.Sprintf("%+.2f%%", d.Percent)
+	case d.Significant:
+		return fmt.Sprintf("%+.2f%% (p=%.3f)", d.Percent, d.P)
+	default:
+		return fmt.Sprintf("~ (p=%.3f)", d.P)
+	}
+}
+
+// Row is one benchmark, or the geomean, across result sets
+type Row struct {
+	Name   string
+	Cells  []Summary // One per set, N is 0 where the benchmark is missing
+	Deltas []Delta   // One per set after the first
+}
+
+// Table compares one unit across result sets
+type Table struct {
+	Unit string
+	Rows []Row
+}
+
+// Compare builds a table per unit, comparing every set with the first one
+func Compare(sets []*Set) []Table {
+	var units []string
+	var keys []Key
+	seenUnit := make(map[string]bool)
+	seenKey := make(map[Key]bool)
+	packages := make(map[string]bool)
+	for _, s := range sets {
+		for _, u := range s.Units {
+			if !seenUnit[u] {
+				seenUnit[u] = true
+				units = append(units, u)
+			}
+		}
+		for _, k := range s.Keys {
+			if !seenKey[k] {
+				seenKey[k] = true
+				keys = append(keys, k)
+				packages[k.Package] = true
+			}
+		}
+	}
+
+	var tables []Table
+	for _, unit := range units {
+		table := Table{Unit: unit}
+		medians := make([][]float64, len(sets)) // For the geomean, benchmarks present everywhere
+
+		for _, key := range keys {
+			row := Row{Name: key.Name}
+			if len(packages) > 1 && key.Package != "" {
+				row.Name = path.Base(key.Package) + "/" + key.Name
+			}
+
+			for _, s := range sets {
+				row.Cells = append(row.Cells, Summarize(s.Values(key, unit)))
+			}
+			present := countPresent(row.Cells)
+			if present == 0 {
+				continue
+			}
+
+			base := sets[0].Values(key, unit)
+			for i, s := range sets[1:] {
+				row.Deltas = append(row.Deltas, delta(base, s.Values(key, unit), row.Cells[0], row.Cells[i+1]))
+			}
+			if present == len(sets) {
+				for i, c := range row.Cells {
+					medians[i] = append(medians[i], c.Median)
+				}
+			}
+			table.Rows = append(table.Rows, row)
+		}
+
+		if len(table.Rows) == 0 {
+			continue
+		}
+		if len(medians[0]) > 1 {
+			table.Rows = append(table.Rows, geomeanRow(medians))
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// delta compares the samples of a benchmark with the base samples
+func delta(base, other []float64, baseSum, otherSum Summary) Delta {
+	if baseSum.N == 0 || otherSum.N == 0 || baseSum.Median == 0 {
+		return Delta{}
+	}
+	p := MannWhitneyU(base, other)
+	return Delta{
+		Percent:     (otherSum.Median - baseSum.Median) / math.Abs(baseSum.Median) * 100,
+		P:           p,
+		Significant: p < Alpha,
+		Valid:       true,
+	}
+}
+
+// geomeanRow summarizes the medians of every set by their geometric mean
+func geomeanRow(medians [][]float64) Row {
+	row := Row{Name: "[Geo mean]"}
+	for _, m := range medians {
+		// A single value: the geomean has no spread of its own
+		row.Cells = append(row.Cells, Summary{Median: Geomean(m), N: 1})
+	}
+	for _, c := range row.Cells[1:] {
+		d := Delta{}
+		if base := row.Cells[0].Median; base > 0 && c.Median > 0 {
+			d = Delta{Percent: (c.Median - base) / base * 100, Significant: true, Valid: true}
+		}
+		row.Deltas = append(row.Deltas, d)
+	}
+	return row
+}
+
+// countPresent counts the cells that have samples
+func countPresent(cells []Summary) int {
+	n := 0
+	for _, c := range cells {
+		if c.N > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// UnitLabel returns the column label of a unit: ns/op is shown as time/op
+func UnitLabel(unit string) string {
+	if unit == "ns/op" {
+		return "time/op"
+	}
+	return unit
+}
+
+// FormatSummary formats a median with its spread in the style of its unit
+func FormatSummary(unit string, s Summary) string {
+	if s.N == 0 {
+		return "-"
+	}
+	value := FormatValue(unit, s.Median)
+	if s.N > 1 {
+		value += fmt.Sprintf(" ± %.0f%%", s.Spread*100)
+	}
+	return value
+}
+
+// FormatValue formats a value of a unit: durations for ns/op, binary sizes
+// for B/op, plain numbers otherwise
+func FormatValue(unit string, v float64) string {
+	switch {
+	case unit == "ns/op":
+		for _, u := range []struct {
+			scale float64
+			name  string
+		}{{1e9, "s"}, {1e6, "ms"}, {1e3, "µs"}} {
+			if math.Abs(v) >= u.scale {
+				return trimFloat(v/u.scale) + u.name
+			}
+		}
+		return trimFloat(v) + "ns"
+	case strings.HasPrefix(unit, "B/"):
+		for _, u := range []struct {
+			scale float64
+			name  string
+		}{{1 << 30, "GiB"}, {1 << 20, "MiB"}, {1 << 10, "KiB"}} {
+			if math.Abs(v) >= u.scale {
+				return trimFloat(v/u.scale) + u.name
+			}
+		}
+		return trimFloat(v) + "B"
+	default:
+		return trimFloat(v)
+	}
+}
+
+// trimFloat formats v with four significant digits
+func trimFloat(v float64) string {
+	return fmt.Sprintf("%.4g", v)
+}
@@ -0,0 +1,137 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// parseSamples builds a result set with one sample line per ns/op value
+func parseSamples(t *testing.T, name string, nsPerOp ...float64) *Set {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("goos: linux\npkg: example.com/m\n")
+	for _, v := range nsPerOp {
+		fmt.Fprintf(&b, "Benchmark%s-8   \t1000000\t%g ns/op\t64 B/op\n", name, v)
+	}
+	b.WriteString("PASS\n")
+
+	set, err := Parse(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		xs   []float64
+		want Summary
+	}{
+		{nil, Summary{}},
+		{[]float64{10}, Summary{Median: 10, N: 1}},
+		{[]float64{12, 10, 11}, Summary{Median: 11, Spread: 1.0 / 11, N: 3}},
+		{[]float64{10, 20, 30, 40}, Summary{Median: 25, Spread: 15.0 / 25, N: 4}},
+	}
+	for _, tt := range tests {
+		got := Summarize(tt.xs)
+		if got.N != tt.want.N || got.Median != tt.want.Median || math.Abs(got.Spread-tt.want.Spread) > 1e-9 {
+			t.Errorf("Summarize(%v) = %+v, want %+v", tt.xs, got, tt.want)
+		}
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name   string
+		xs, ys []float64
+		min    float64
+		max    float64
+	}{
+		{"separated", []float64{1, 2, 3, 4, 5, 6, 7, 8}, []float64{11, 12, 13, 14, 15, 16, 17, 18}, 0, 0.001},
+		{"interleaved", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 0.5, 1},
+		{"identical", []float64{5, 5, 5, 5}, []float64{5, 5, 5, 5}, 1, 1},
+		{"too few samples", []float64{1}, []float64{100, 200}, 1, 1},
+	}
+	for _, tt := range tests {
+		p := MannWhitneyU(tt.xs, tt.ys)
+		if p < tt.min || p > tt.max {
+			t.Errorf("%s: p = %g, want in [%g, %g]", tt.name, p, tt.min, tt.max)
+		}
+		if q := MannWhitneyU(tt.ys, tt.xs); math.Abs(p-q) > 1e-12 {
+			t.Errorf("%s: p = %g one way and %g the other", tt.name, p, q)
+		}
+	}
+}
+
+func TestCompareSignificance(t *testing.T) {
+	base := parseSamples(t, "Parse", 100, 101, 99, 100, 102, 98, 100, 101, 99, 100)
+	tests := []struct {
+		name        string
+		other       *Set
+		significant bool
+		percent     float64
+	}{
+		{"faster", parseSamples(t, "Parse", 80, 81, 79, 80, 82, 78, 80, 81, 79, 80), true, -20},
+		{"noise", parseSamples(t, "Parse", 101, 99, 100, 102, 98, 100, 99, 101, 100, 100), false, 0},
+		{"too few samples", parseSamples(t, "Parse", 50), false, -50},
+	}
+
+	for _, tt := range tests {
+		tables := Compare([]*Set{base, tt.other})
+		if len(tables) != 2 || tables[0].Unit != "ns/op" || tables[1].Unit != "B/op" {
+			t.Fatalf("%s: got %d tables, want ns/op and B/op", tt.name, len(tables))
+		}
+
+		rows := tables[0].Rows
+		if len(rows) != 1 || rows[0].Name != "Parse" {
+			t.Fatalf("%s: rows = %+v, want only Parse", tt.name, rows)
+		}
+		d := rows[0].Deltas[0]
+		if !d.Valid || d.Significant != tt.significant {
+			t.Errorf("%s: delta = %+v, want significant %v", tt.name, d, tt.significant)
+		}
+		if math.Abs(d.Percent-tt.percent) > 1e-9 {
+			t.Errorf("%s: change = %g%%, want %g%%", tt.name, d.Percent, tt.percent)
+		}
+		if !tt.significant && !strings.HasPrefix(d.String(), "~") {
+			t.Errorf("%s: String() = %q, want ~", tt.name, d.String())
+		}
+	}
+}
+
+func TestCompareMissingBenchmark(t *testing.T) {
+	base := parseSamples(t, "Parse", 100, 101, 99)
+	other := parseSamples(t, "Format", 10, 11, 9)
+
+	rows := Compare([]*Set{base, other})[0].Rows
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want Parse and Format without a geomean", len(rows))
+	}
+	for _, row := range rows {
+		if row.Deltas[0].Valid {
+			t.Errorf("%s: delta %+v is valid, want none for a missing side", row.Name, row.Deltas[0])
+		}
+	}
+}
+
+func TestCompareGeomean(t *testing.T) {
+	parse := func(scale float64) *Set {
+		set, err := Parse(strings.NewReader(fmt.Sprintf(
+			"BenchmarkA-8 100 %g ns/op\nBenchmarkB-8 100 %g ns/op\n", 10*scale, 1000*scale)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return set
+	}
+
+	rows := Compare([]*Set{parse(1), parse(2)})[0].Rows
+	geomean := rows[len(rows)-1]
+	if geomean.Name != "[Geo mean]" {
+		t.Fatalf("last row is %q, want the geomean", geomean.Name)
+	}
+	if math.Abs(geomean.Cells[0].Median-100) > 1e-9 || math.Abs(geomean.Deltas[0].Percent-100) > 1e-9 {
+		t.Errorf("geomean = %+v, want 100 and +100%%", geomean)
+	}
+}
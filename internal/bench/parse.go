@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// procsSuffix matches the -GOMAXPROCS suffix of a benchmark name
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// Key identifies a benchmark across result sets
+type Key struct {
+	Package string // From the "pkg:" line, empty if there was none
+	Name    string // Without the Benchmark prefix and -GOMAXPROCS suffix
+}
+
+// Set holds the samples of one benchmark run, per benchmark and unit
+type Set struct {
+	Keys   []Key    // In the order benchmarks first appeared
+	Units  []string // In the order units first appeared
+	values map[Key]map[string][]float64
+}
+
+// Values returns the samples of a benchmark for a unit
+func (s *Set) Values(key Key, unit string) []float64 {
+	return s.values[key][unit]
+}
+
+// Parse reads benchmark results in the standard 'go test -bench' format.
+// Lines that are not results are ignored.
+func Parse(r io.Reader) (*Set, error) {
+	set := &Set{values: make(map[Key]map[string][]float64)}
+	seenUnit := make(map[string]bool)
+	pkg := ""
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if rest, ok := strings.CutPrefix(line, "pkg:"); ok {
+			pkg = strings.TrimSpace(rest)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.ParseInt(fields[1], 10, 64); err != nil {
+			continue
+		}
+
+		name := procsSuffix.ReplaceAllString(strings.TrimPrefix(fields[0], "Benchmark"), "")
+		key := Key{Package: pkg, Name: name}
+		units, ok := set.values[key]
+		if !ok {
+			units = make(map[string][]float64)
+			set.values[key] = units
+			set.Keys = append(set.Keys, key)
+		}
+
+		// Value/unit pairs follow the iteration count
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			unit := fields[i+1]
+			units[unit] = append(units[unit], value)
+			if !seenUnit[unit] {
+				seenUnit[unit] = true
+				set.Units = append(set.Units, unit)
+			}
+		}
+	}
+	return set, scanner.Err()
+}
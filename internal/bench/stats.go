@@ -0,0 +1,110 @@
+package bench
+
+import (
+	"math"
+	"sort"
+)
+
+// Alpha is the significance level below which a difference is reported
+const Alpha = 0.05
+
+// Summary describes the samples of one benchmark and unit
+type Summary struct {
+	Median float64
+	Spread float64 // Largest deviation from the median, relative to it
+	N      int
+}
+
+// Summarize returns the median of xs and how far the samples spread around it
+func Summarize(xs []float64) Summary {
+	if len(xs) == 0 {
+		return Summary{}
+	}
+
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+
+	var median float64
+	if n := len(sorted); n%2 == 1 {
+		median = sorted[n/2]
+	} else {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	spread := 0.0
+	if median != 0 {
+		spread = math.Max(median-sorted[0], sorted[len(sorted)-1]-median) / math.Abs(median)
+	}
+	return Summary{Median: median, Spread: spread, N: len(xs)}
+}
+
+// MannWhitneyU returns the two-sided p-value of the Mann-Whitney U test for
+// xs and ys coming from the same distribution, using the normal
+// approximation with tie and continuity correction. It returns 1 when
+// either sample is too small to tell anything apart.
+func MannWhitneyU(xs, ys []float64) float64 {
+	n1, n2 := len(xs), len(ys)
+	if n1 < 2 || n2 < 2 {
+		return 1
+	}
+
+	type sample struct {
+		value float64
+		first bool
+	}
+	all := make([]sample, 0, n1+n2)
+	for _, x := range xs {
+		all = append(all, sample{x, true})
+	}
+	for _, y := range ys {
+		all = append(all, sample{y, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Rank with ties sharing their average rank
+	n := float64(n1 + n2)
+	rankSum, tieTerm := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	u := rankSum - float64(n1*(n1+1))/2
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// Geomean returns the geometric mean of positive values, or 0 if any is not
+func Geomean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		if x <= 0 {
+			return 0
+		}
+		sum += math.Log(x)
+	}
+	return math.Exp(sum / float64(len(xs)))
+}
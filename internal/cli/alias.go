@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wenzzy/govm/internal/config"
 	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
 )
 
 var aliasCmd = &cobra.Command{
@@ -19,6 +20,7 @@ Built-in aliases: stable, latest (auto-updated)
 Examples:
   govm alias                   List all aliases
   govm alias dev 1.22.0        Create alias 'dev' for version 1.22.0
+  govm alias lts '~1.22'       Alias a constraint, resolved each time it is used
   govm alias rm dev            Remove alias 'dev'
   g alias lts 1.21.0           Short form`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -50,13 +52,24 @@ func listAliases() error {
 		return nil
 	}
 
+	mgr, err := version.NewManager()
+	if err != nil {
+		return err
+	}
+
 	ui.PrintHeader("Aliases")
 
+	var dangling int
 	table := ui.NewTable("Alias", "Version")
-	for name, version := range aliases {
-		displayVersion := version
-		if version == "" {
+	for name, target := range aliases {
+		displayVersion := target
+		if target == "" {
 			displayVersion = ui.Dim.Sprint("(not set)")
+		} else if matched, err := mgr.ResolveInstalled(target); err == nil && len(matched) == 0 {
+			displayVersion = target + ui.Warning.Sprint(" (not installed)")
+			dangling++
+		} else if err == nil && version.IsConstraint(target) {
+			displayVersion = target + ui.Dim.Sprintf(" (%s)", matched[0])
 		}
 
 		// Mark reserved aliases
@@ -71,6 +84,12 @@ func listAliases() error {
 	}
 	table.Render()
 
+	if dangling > 0 {
+		ui.Println()
+		ui.PrintWarning("%d alias(es) point to versions that are not installed", dangling)
+		ui.PrintHint("Re-point with 'govm alias <name> <version>' or remove with 'govm alias rm <name>'")
+	}
+
 	return nil
 }
 
@@ -88,19 +107,30 @@ func showAlias(name string) error {
 	return nil
 }
 
-func createAlias(name, version string) error {
+func createAlias(name, spec string) error {
 	if err := config.ValidateAliasName(name); err != nil {
 		return err
 	}
 
 	// Normalize version
-	version = config.NormalizeVersion(version)
+	target := config.NormalizeVersion(spec)
 
-	if err := config.SetAlias(name, version); err != nil {
+	// Constraints are stored as written and re-evaluated on every use
+	if version.IsConstraint(target) {
+		if _, err := version.ParseConstraint(target); err != nil {
+			return err
+		}
+	}
+
+	if err := config.SetAlias(name, target); err != nil {
 		return err
 	}
 
-	ui.PrintSuccess("Created alias '%s' -> %s", name, version)
+	if version.IsConstraint(target) {
+		ui.PrintSuccess("Created alias '%s' -> %s (re-evaluated on use)", name, target)
+	} else {
+		ui.PrintSuccess("Created alias '%s' -> %s", name, target)
+	}
 	return nil
 }
 
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/bench"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var benchSave string
+
+var benchCmd = &cobra.Command{
+	Use:   "bench <version|constraint>... -- <command> [args...]",
+	Short: "Compare benchmark results across Go versions",
+	Long: `Run a benchmark command with every given Go version, one after another,
+and compare the results in the style of benchstat.
+
+Versions are expanded like 'govm matrix'. The command runs in the same
+environment as 'govm exec' and must print standard 'go test -bench'
+output. For every unit (time/op, B/op, allocs/op and custom metrics) the
+median of each version is shown with its spread, followed by the change
+from the first version. A change is only reported when the Mann-Whitney U
+test finds it significant (p < 0.05); otherwise it is shown as ~. Use
+-count=6 or more for meaningful results.
+
+--save writes the raw output of each version to <dir>/go<version>.txt,
+which 'govm bench compare' (or benchstat) can compare later.
+
+Examples:
+  govm bench 1.22 1.23 -- go test -run='^$' -bench=. -count=10 ./pkg
+  govm bench 1.23 --save results -- go test -run='^$' -bench=. -count=10 ./...
+  govm bench compare results/go1.22.8.txt results/go1.23.4.txt`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dash := cmd.ArgsLenAtDash()
+		if dash < 1 || dash == len(args) {
+			return fmt.Errorf("usage: govm bench <version>... -- <command> [args...]")
+		}
+		specs, command := args[:dash], args[dash:]
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		spinner := ui.NewSpinner("Resolving versions...")
+		spinner.Start()
+		versions, err := mgr.ExpandMatrix(specs)
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+		if len(versions) < 2 && benchSave == "" {
+			return fmt.Errorf("need at least two versions to compare (or --save to keep the results for later)")
+		}
+
+		for _, ver := range versions {
+			if _, err := mgr.EnsureInstalled(ver); err != nil {
+				return err
+			}
+		}
+
+		var labels []string
+		var sets []*bench.Set
+		for _, ver := range versions {
+			ui.PrintInfo("Benchmarking with Go %s...", ver)
+			output, err := benchRun(mgr, ver, command)
+			if err != nil {
+				return err
+			}
+
+			if benchSave != "" {
+				path, err := saveBenchOutput(ver, output)
+				if err != nil {
+					return err
+				}
+				ui.PrintSuccess("Saved results to %s", path)
+			}
+
+			set, err := bench.Parse(bytes.NewReader(output))
+			if err != nil {
+				return err
+			}
+			if len(set.Keys) == 0 {
+				return fmt.Errorf("no benchmark results in the output of Go %s", ver)
+			}
+			labels = append(labels, "go"+ver)
+			sets = append(sets, set)
+		}
+
+		if len(sets) > 1 {
+			printBenchComparison(labels, sets)
+		}
+		return nil
+	},
+}
+
+var benchCompareCmd = &cobra.Command{
+	Use:   "compare <file> <file>...",
+	Short: "Compare saved benchmark results",
+	Long: `Compare files of 'go test -bench' output, such as those written by
+'govm bench --save', against the first file.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var labels []string
+		var sets []*bench.Set
+		for _, path := range args {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			set, err := bench.Parse(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			if len(set.Keys) == 0 {
+				return fmt.Errorf("no benchmark results in %s", path)
+			}
+			labels = append(labels, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+			sets = append(sets, set)
+		}
+
+		printBenchComparison(labels, sets)
+		return nil
+	},
+}
+
+// benchRun runs command with a version, streaming its output with a
+// prefix, and returns the output. A non-zero exit is reported but the
+// results printed until then are kept.
+func benchRun(mgr *version.Manager, ver string, command []string) ([]byte, error) {
+	c, err := versionExecCmd(mgr, ver, command)
+	if err != nil {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+	out := &prefixWriter{mu: &sync.Mutex{}, out: os.Stdout, prefix: ui.Dim.Sprintf("[%s] ", ver)}
+	w := io.MultiWriter(out, &output)
+	c.Stdout = w
+	c.Stderr = w
+
+	err = c.Run()
+	out.Flush()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		ui.PrintWarning("Command exited with %d on Go %s", exitErr.ExitCode(), ver)
+	} else if err != nil {
+		return nil, err
+	}
+	return output.Bytes(), nil
+}
+
+// saveBenchOutput writes the raw output of a version to the --save directory
+func saveBenchOutput(ver string, output []byte) (string, error) {
+	if err := os.MkdirAll(benchSave, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(benchSave, "go"+ver+".txt")
+	return path, os.WriteFile(path, output, 0644)
+}
+
+// printBenchComparison prints a table per unit comparing every set with
+// the first one
+func printBenchComparison(labels []string, sets []*bench.Set) {
+	for _, table := range bench.Compare(sets) {
+		ui.PrintHeader(bench.UnitLabel(table.Unit))
+
+		headers := append([]string{"Benchmark"}, labels...)
+		for _, label := range labels[1:] {
+			if len(labels) == 2 {
+				label = ""
+			}
+			headers = append(headers, strings.TrimSpace(label+" vs "+labels[0]))
+		}
+
+		t := ui.NewTable(headers...)
+		for _, row := range table.Rows {
+			values := []string{row.Name}
+			for _, cell := range row.Cells {
+				values = append(values, bench.FormatSummary(table.Unit, cell))
+			}
+			for _, d := range row.Deltas {
+				values = append(values, d.String())
+			}
+			t.AddRow(values...)
+		}
+		t.Render()
+	}
+	ui.Println()
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&benchSave, "save", "", "Save the raw output of each version to `dir`")
+	benchCmd.AddCommand(benchCompareCmd)
+}
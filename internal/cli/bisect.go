@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// bisectSkip is the exit code that marks a version as untestable, as in
+// 'git bisect run'
+const bisectSkip = 125
+
+var (
+	bisectGood string
+	bisectBad  string
+)
+
+var bisectCmd = &cobra.Command{
+	Use:   "bisect --good <version> --bad <version> -- <command> [args...]",
+	Short: "Find the first Go release where a command starts failing",
+	Long: `Binary-search the stable releases between a good and a bad Go version
+for the first one where a command fails.
+
+The command runs in the same environment as 'govm exec'. Its exit code
+decides each step, as with 'git bisect run':
+  0          the version is good
+  125        the version cannot be tested and is skipped
+  1-127      the version is bad
+  128-255    bisecting is aborted (as is a command killed by a signal)
+
+Releases come from the go.dev release index and are installed as needed.
+The good and bad versions themselves are not run.
+
+Examples:
+  govm bisect --good 1.21.0 --bad 1.22.3 -- go test ./pkg -run TestParse
+  govm bisect --good 1.20 --bad 1.22 -- ./repro.sh`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bisectGood == "" || bisectBad == "" {
+			return fmt.Errorf("both --good and --bad are required")
+		}
+		if cmd.ArgsLenAtDash() != 0 {
+			return fmt.Errorf("usage: govm bisect --good <version> --bad <version> -- <command> [args...]")
+		}
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		good := config.NormalizeVersion(config.ResolveVersion(bisectGood))
+		bad := config.NormalizeVersion(config.ResolveVersion(bisectBad))
+
+		spinner := ui.NewSpinner("Fetching releases...")
+		spinner.Start()
+		releases, err := version.ReleasesBetween(good, bad)
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+
+		// candidates[lo] is known good, candidates[hi] known bad
+		candidates := append([]string{good}, releases...)
+		lo, hi := 0, len(candidates)-1
+		skipped := make(map[int]bool)
+		var log []string
+
+		for {
+			mid := bisectNext(lo, hi, skipped)
+			if mid < 0 {
+				break
+			}
+			ver := candidates[mid]
+
+			ui.PrintHeader(fmt.Sprintf("Testing Go %s (%d release(s) left, about %d step(s))", ver, hi-lo-1, bisectSteps(hi-lo-1)))
+
+			code, err := bisectRun(mgr, ver, args)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case code == 0:
+				ui.PrintSuccess("Go %s is good", ver)
+				log = append(log, ver+" good")
+				lo = mid
+			case code == bisectSkip:
+				ui.PrintWarning("Go %s skipped", ver)
+				log = append(log, ver+" skip")
+				skipped[mid] = true
+			case code > 0 && code < 128:
+				ui.PrintWarning("Go %s is bad (exit %d)", ver, code)
+				log = append(log, ver+" bad")
+				hi = mid
+			default:
+				return fmt.Errorf("bisect aborted: command exited with %d on Go %s", code, ver)
+			}
+		}
+
+		ui.PrintHeader("Bisect")
+		for _, entry := range log {
+			ui.PrintBullet(entry)
+		}
+		ui.Println()
+
+		if hi-lo > 1 {
+			// Only skipped versions are left between the last good and bad ones
+			ui.PrintWarning("Could not narrow down further, the first bad release is one of: %s",
+				strings.Join(candidates[lo+1:hi+1], ", "))
+			return nil
+		}
+
+		first := candidates[hi]
+		ui.PrintSuccess("First bad release: Go %s (last good: Go %s)", ui.GreenBold.Sprint(first), candidates[lo])
+		ui.PrintKeyValue("Release notes", ui.Path.Sprint(version.ReleaseNotesURL(first)))
+		return nil
+	},
+}
+
+// bisectNext returns the untested, unskipped index closest to the middle
+// of (lo, hi), or -1 when there is none
+func bisectNext(lo, hi int, skipped map[int]bool) int {
+	mid := (lo + hi) / 2
+	for d := 0; mid-d > lo || mid+d < hi; d++ {
+		if i := mid - d; i > lo && !skipped[i] {
+			return i
+		}
+		if i := mid + d; i > lo && i < hi && !skipped[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+// bisectSteps estimates the remaining steps for n untested releases
+func bisectSteps(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return bits.Len(uint(n))
+}
+
+// bisectRun installs a version if needed and runs command with it,
+// returning the exit code
+func bisectRun(mgr *version.Manager, ver string, command []string) (int, error) {
+	if !mgr.IsInstalled(ver) {
+		if err := mgr.Install(ver, false, true); err != nil {
+			return 0, err
+		}
+	}
+
+	c, err := versionExecCmd(mgr, ver, command)
+	if err != nil {
+		return 0, err
+	}
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	err = c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to run %s: %w", command[0], err)
+	}
+	return 0, nil
+}
+
+func init() {
+	bisectCmd.Flags().StringVar(&bisectGood, "good", "", "A Go version where the command succeeds")
+	bisectCmd.Flags().StringVar(&bisectBad, "bad", "", "A Go version where the command fails")
+}
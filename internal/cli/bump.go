@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/scan"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// goLineRegex matches a version usable as is in a go directive
+var goLineRegex = regexp.MustCompile(`^\d+\.\d+(?:\.\d+)?(?:rc\d+)?$`)
+
+var (
+	bumpToolchainOnly bool
+	bumpAllModules    bool
+	bumpTidy          bool
+	bumpForce         bool
+)
+
+var bumpCmd = &cobra.Command{
+	Use:   "bump <version>",
+	Short: "Move a project to a Go version and install it",
+	Long: `Rewrite the go and toolchain directives of the project in the current
+directory, install the version and switch to it.
+
+The go directive is set to the version as given ("1.23" or "1.23.4"), and
+the toolchain directive to the newest matching release on go.dev
+("go1.23.4") when that is newer; an outdated toolchain line is dropped.
+Offline, the newest matching installed version is used. Comments and
+layout of the files are kept. Both go.work and go.mod of the current directory are
+updated, so a workspace never ends up older than its modules.
+
+  --toolchain-only  only change the toolchain line, keeping the go line as
+                    the minimum for users of the module
+  --all-modules     also update every module of the go.work workspace, or
+                    every go.mod below the current directory without one
+  --tidy            run 'go mod tidy' with the new version afterwards
+  --force           allow lowering a go or toolchain line
+
+Examples:
+  govm bump 1.23                      Require Go 1.23 and use the latest 1.23.x
+  govm bump 1.23.4 --all-modules      Move the whole workspace
+  govm bump 1.23.4 --toolchain-only   Build with 1.23.4, keep the go line
+  govm bump 1.22 --force              Go back to 1.22`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := args[0]
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		targets, err := bumpTargets(dir, bumpAllModules)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no go.mod or go.work in %s", dir)
+		}
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		// The newest release matching spec, so "1.23" moves to the latest
+		// 1.23.x even when an older patch is installed
+		ver, err := mgr.ResolveRelease(spec)
+		if err != nil {
+			return err
+		}
+
+		goVersion := ""
+		if !bumpToolchainOnly {
+			goVersion = config.NormalizeVersion(config.ResolveVersion(spec))
+			if !goLineRegex.MatchString(goVersion) {
+				goVersion = ver // A constraint or alias: require the release itself
+			}
+		}
+
+		var bumps []*version.Bump
+		var lowered []string
+		for _, path := range targets {
+			b, err := version.PlanBump(path, goVersion, ver)
+			if err != nil {
+				return err
+			}
+			if b.Downgrade() {
+				lowered = append(lowered, fmt.Sprintf("%s (%s -> %s)", bumpRel(dir, path), b.Before.Version(), b.After.Version()))
+			}
+			bumps = append(bumps, b)
+		}
+		if len(lowered) > 0 && !bumpForce {
+			return fmt.Errorf("refusing to lower the Go version of %s (use --force)", strings.Join(lowered, ", "))
+		}
+
+		// Install only once the bump is allowed
+		if err := mgr.Install(ver, false, true); err != nil {
+			return err
+		}
+
+		changed := 0
+		for _, b := range bumps {
+			if !b.Changed() {
+				continue
+			}
+			mode := os.FileMode(0644)
+			if info, err := os.Stat(b.Path); err == nil {
+				mode = info.Mode().Perm()
+			}
+			if err := os.WriteFile(b.Path, b.New, mode); err != nil {
+				return err
+			}
+			printBumpDiff(bumpRel(dir, b.Path), b)
+			changed++
+		}
+
+		ui.Println()
+		if changed == 0 {
+			ui.PrintInfo("Already at Go %s", ver)
+		} else {
+			ui.PrintSuccess("Updated %d file(s) to Go %s", changed, ver)
+		}
+
+		if err := mgr.Use(ver); err != nil {
+			return err
+		}
+
+		if bumpTidy {
+			return bumpTidyModules(mgr, ver, dir, bumps)
+		}
+		return nil
+	},
+}
+
+// bumpTargets returns the go.work and go.mod files to update: those in dir,
+// and with all every module of the workspace, or every go.mod below dir
+// when there is no go.work
+func bumpTargets(dir string, all bool) ([]string, error) {
+	var targets []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if _, err := os.Stat(path); err == nil && !seen[path] {
+			seen[path] = true
+			targets = append(targets, path)
+		}
+	}
+
+	work := filepath.Join(dir, version.GoWorkFile)
+	add(work)
+	add(filepath.Join(dir, version.GoModFile))
+	if !all {
+		return targets, nil
+	}
+
+	if seen[work] {
+		modules, err := version.WorkspaceModules(work)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range modules {
+			add(filepath.Join(m, version.GoModFile))
+		}
+		return targets, nil
+	}
+
+	mods, err := scan.Files(dir, func(name string) bool { return name == version.GoModFile })
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range mods {
+		add(path)
+	}
+	return targets, nil
+}
+
+// bumpTidyModules runs 'go mod tidy' with ver in every bumped module
+func bumpTidyModules(mgr *version.Manager, ver, dir string, bumps []*version.Bump) error {
+	failed := 0
+	for _, b := range bumps {
+		if filepath.Base(b.Path) != version.GoModFile {
+			continue
+		}
+		moduleDir := filepath.Dir(b.Path)
+		ui.PrintInfo("Running go mod tidy in %s...", bumpRel(dir, moduleDir))
+
+		c, err := versionExecCmd(mgr, ver, []string{"go", "mod", "tidy"})
+		if err != nil {
+			return err
+		}
+		c.Dir = moduleDir
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			ui.PrintError("go mod tidy failed in %s: %s", bumpRel(dir, moduleDir), err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("go mod tidy failed in %d module(s)", failed)
+	}
+	return nil
+}
+
+// printBumpDiff prints the lines a bump removed and added
+func printBumpDiff(name string, b *version.Bump) {
+	ui.Println()
+	fmt.Println(ui.Path.Sprint(name))
+	for _, line := range lineDiff(strings.Split(string(b.Old), "\n"), strings.Split(string(b.New), "\n")) {
+		switch {
+		case strings.TrimSpace(line[1:]) == "":
+			// Blank lines around a new or dropped directive
+		case strings.HasPrefix(line, "-"):
+			fmt.Println("  " + ui.Error.Sprint(line))
+		default:
+			fmt.Println("  " + ui.Green.Sprint(line))
+		}
+	}
+}
+
+// lineDiff returns the lines removed from a ("-line") and added in b
+// ("+line"), in file order, based on their longest common subsequence
+func lineDiff(a, b []string) []string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	return diff
+}
+
+// bumpRel returns path relative to dir for display
+func bumpRel(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func init() {
+	bumpCmd.Flags().BoolVar(&bumpToolchainOnly, "toolchain-only", false, "Only change the toolchain directive")
+	bumpCmd.Flags().BoolVar(&bumpAllModules, "all-modules", false, "Update every module of the workspace or below the current directory")
+	bumpCmd.Flags().BoolVar(&bumpTidy, "tidy", false, "Run 'go mod tidy' with the new version")
+	bumpCmd.Flags().BoolVar(&bumpForce, "force", false, "Allow lowering the Go version")
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -16,14 +17,21 @@ var configCmd = &cobra.Command{
 	Long: `View and modify govm configuration settings.
 
 Available settings:
-  auto_install     - Automatically install missing versions (true/false)
-  inherit_version  - Search parent directories for go.mod/go.work (true/false)
-  default_version  - Default Go version to use
+  auto_install        - Automatically install missing versions (true/false)
+  inherit_version     - Search parent directories for go.mod/go.work (true/false)
+  inherit_stop        - Where the parent search stops: git (repository root), home (comma-separated, or none)
+  inherit_max_depth   - Parent directories searched at most (0 for no limit)
+  ceiling_directories - Directories the parent search never enters (colon-separated, or none)
+  default_version     - Default Go version to use
+  switch_scope        - Where auto-switch applies a version: global (symlink) or session (current shell)
+  on_leave            - Version to restore when leaving a project: default, previous or keep
+  isolate             - Settings kept per version: gobin, gopath, goenv, modcache (comma-separated, or none)
 
 Examples:
   govm config                           Show all settings
   govm config get auto_install          Get a specific setting
   govm config set inherit_version true  Enable version inheritance
+  govm config set ceiling_directories ~/src:/tmp
   g config set auto_install false       Disable auto-install`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return showConfig()
@@ -67,7 +75,13 @@ func showConfig() error {
 	ui.PrintHeader("Configuration")
 	ui.PrintKeyValue("auto_install", formatBool(cfg.AutoInstall))
 	ui.PrintKeyValue("inherit_version", formatBool(cfg.InheritVersion))
+	ui.PrintKeyValue("inherit_stop", formatString(strings.Join(cfg.InheritStop, ",")))
+	ui.PrintKeyValue("inherit_max_depth", formatDepth(cfg.InheritDepth))
+	ui.PrintKeyValue("ceiling_directories", formatString(strings.Join(cfg.Ceilings, ":")))
 	ui.PrintKeyValue("default_version", formatString(cfg.DefaultVersion))
+	ui.PrintKeyValue("switch_scope", formatString(cfg.SwitchScope))
+	ui.PrintKeyValue("on_leave", formatString(cfg.OnLeave))
+	ui.PrintKeyValue("isolate", formatString(strings.Join(cfg.Isolate, ",")))
 
 	paths, _ := config.GetPaths()
 	ui.Println()
@@ -84,8 +98,20 @@ func getConfig(key string) error {
 		fmt.Println(cfg.AutoInstall)
 	case "inherit_version", "inheritversion", "inherit":
 		fmt.Println(cfg.InheritVersion)
+	case "inherit_stop":
+		fmt.Println(strings.Join(cfg.InheritStop, ","))
+	case "inherit_max_depth":
+		fmt.Println(cfg.InheritDepth)
+	case "ceiling_directories", "ceilings":
+		fmt.Println(strings.Join(cfg.Ceilings, ":"))
 	case "default_version", "defaultversion", "default":
 		fmt.Println(cfg.DefaultVersion)
+	case "switch_scope", "switchscope", "scope":
+		fmt.Println(cfg.SwitchScope)
+	case "on_leave", "onleave":
+		fmt.Println(cfg.OnLeave)
+	case "isolate":
+		fmt.Println(strings.Join(cfg.Isolate, ","))
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -118,17 +144,119 @@ func setConfig(key, value string) error {
 			ui.PrintHint("govm will only check the current directory for go.mod/go.work")
 		}
 
+	case "inherit_stop":
+		stops, err := parseInheritStop(value)
+		if err != nil {
+			return err
+		}
+		cfg.InheritStop = stops
+		ui.PrintSuccess("Set inherit_stop = %s", formatString(strings.Join(stops, ",")))
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
+	case "inherit_max_depth":
+		depth, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || depth < 0 {
+			return fmt.Errorf("invalid value for inherit_max_depth: %s (use a number, 0 for no limit)", value)
+		}
+		cfg.InheritDepth = depth
+		ui.PrintSuccess("Set inherit_max_depth = %s", formatDepth(depth))
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
+	case "ceiling_directories", "ceilings":
+		value = strings.TrimSpace(value)
+		cfg.Ceilings = nil
+		if value != "" && value != "none" {
+			for _, dir := range filepath.SplitList(value) {
+				if dir = strings.TrimSpace(dir); dir != "" {
+					cfg.Ceilings = append(cfg.Ceilings, dir)
+				}
+			}
+		}
+		ui.PrintSuccess("Set ceiling_directories = %s", formatString(strings.Join(cfg.Ceilings, ":")))
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
 	case "default_version", "defaultversion", "default":
 		cfg.DefaultVersion = config.NormalizeVersion(value)
 		ui.PrintSuccess("Set default_version = %s", cfg.DefaultVersion)
 
+	case "switch_scope", "switchscope", "scope":
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != config.ScopeGlobal && value != config.ScopeSession {
+			return fmt.Errorf("invalid value for switch_scope: %s (use global/session)", value)
+		}
+		cfg.SwitchScope = value
+		ui.PrintSuccess("Set switch_scope = %s", value)
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
+	case "on_leave", "onleave":
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != config.LeaveDefault && value != config.LeavePrevious && value != config.LeaveKeep {
+			return fmt.Errorf("invalid value for on_leave: %s (use default/previous/keep)", value)
+		}
+		cfg.OnLeave = value
+		ui.PrintSuccess("Set on_leave = %s", value)
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
+	case "isolate":
+		settings, err := parseIsolate(value)
+		if err != nil {
+			return err
+		}
+		cfg.Isolate = settings
+		ui.PrintSuccess("Set isolate = %s", formatString(strings.Join(settings, ",")))
+		ui.PrintHint("Restart your shell or re-run 'govm init' to apply")
+
 	default:
-		return fmt.Errorf("unknown config key: %s\n\nAvailable keys: auto_install, inherit_version, default_version", key)
+		return fmt.Errorf("unknown config key: %s\n\nAvailable keys: auto_install, inherit_version, inherit_stop, inherit_max_depth, ceiling_directories, default_version, switch_scope, on_leave, isolate", key)
 	}
 
 	return config.Save(cfg)
 }
 
+// parseIsolate parses a comma-separated list of isolate settings ("none" clears it)
+func parseIsolate(value string) ([]string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+
+	var settings []string
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		valid := false
+		for _, known := range config.IsolateSettings {
+			if s == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid value for isolate: %s (use %s or none)", s, strings.Join(config.IsolateSettings, ", "))
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+// parseInheritStop parses a comma-separated list of inherit_stop markers
+// ("none" clears it)
+func parseInheritStop(value string) ([]string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	stops := []string{} // Saved as an empty list, not dropped back to the default
+	if value == "" || value == "none" {
+		return stops, nil
+	}
+
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s != config.StopGit && s != config.StopHome {
+			return nil, fmt.Errorf("invalid value for inherit_stop: %s (use %s or none)", s, strings.Join(config.InheritStops, ", "))
+		}
+		stops = append(stops, s)
+	}
+	return stops, nil
+}
+
 func parseBool(s string) (bool, error) {
 	s = strings.ToLower(strings.TrimSpace(s))
 	switch s {
@@ -148,6 +276,13 @@ func formatBool(b bool) string {
 	return ui.Dim.Sprint("false")
 }
 
+func formatDepth(depth int) string {
+	if depth == 0 {
+		return ui.Dim.Sprint("0 (no limit)")
+	}
+	return strconv.Itoa(depth)
+}
+
 func formatString(s string) string {
 	if s == "" {
 		return ui.Dim.Sprint("(not set)")
@@ -7,11 +7,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/shell"
 	"github.com/wenzzy/govm/internal/ui"
 	"github.com/wenzzy/govm/internal/version"
 )
 
-var currentPath bool
+var (
+	currentPath    bool
+	currentExplain bool
+)
 
 var currentCmd = &cobra.Command{
 	Use:     "current",
@@ -22,8 +26,13 @@ var currentCmd = &cobra.Command{
 Examples:
   govm current                Show current version
   govm current --path         Show path to current Go binary
+  govm current --explain      Show why this version was chosen
   g current                   Short form`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if currentExplain {
+			return explainResolution("")
+		}
+
 		mgr, err := version.NewManager()
 		if err != nil {
 			return err
@@ -34,6 +43,17 @@ Examples:
 			return err
 		}
 
+		// A session version overrides the global symlink in this shell,
+		// resolved as --explain, shims and the hook do so that project files
+		// outrank a version the auto-switch hook set for another directory
+		global := current
+		res, _ := version.Resolve("")
+		var sessionVer, sessionSource string
+		if res != nil && res.Source == version.SourceSession {
+			sessionVer, sessionSource = res.Raw, res.Origin
+			current = sessionVer
+		}
+
 		if current == "" {
 			ui.PrintWarning("No Go version is currently active")
 			ui.PrintHint("Run 'govm use <version>' to activate a version")
@@ -57,6 +77,12 @@ Examples:
 		if goVersion != "" && goVersion != current {
 			ui.PrintKeyValue("Go version", goVersion)
 		}
+		if sessionVer != "" {
+			ui.PrintKeyValue("Scope", fmt.Sprintf("session (%s)", sessionSource))
+			if global != "" && global != current {
+				ui.PrintKeyValue("Global", global)
+			}
+		}
 
 		// Show default if different
 		defaultVer := mgr.GetDefault()
@@ -64,6 +90,13 @@ Examples:
 			ui.PrintKeyValue("Default", defaultVer)
 		}
 
+		// Show per-version GOBIN/GOPATH/GOENV
+		if isolation, _ := shell.IsolationEnv(current); len(isolation) > 0 {
+			for _, v := range isolation {
+				ui.PrintKeyValue(v.Key, ui.Path.Sprint(v.Value))
+			}
+		}
+
 		// Show aliases pointing to current version
 		aliases := findAliasesForVersion(current)
 		if len(aliases) > 0 {
@@ -71,14 +104,16 @@ Examples:
 		}
 
 		// Check for project version
-		projectVer, source, err := version.DetectVersion("")
-		if err == nil && projectVer != "" {
-			normalizedProject, _ := version.NormalizeDetectedVersion(projectVer)
-			if normalizedProject != current {
-				ui.PrintWarning("Project requires Go %s (from %s)", normalizedProject, source)
+		if res != nil && res.Source == version.SourceProject {
+			switch res.Version {
+			case current:
+				ui.PrintInfo("Matches project requirement from %s", ui.Dim.Sprint(res.Origin))
+			case "":
+				ui.PrintWarning("Project requires Go %s (from %s), which is not installed", res.Raw, res.Origin)
+				ui.PrintHint("Run 'govm use .' to install and switch to project version")
+			default:
+				ui.PrintWarning("Project requires Go %s (from %s)", res.Version, res.Origin)
 				ui.PrintHint("Run 'govm use .' to switch to project version")
-			} else {
-				ui.PrintInfo("Matches project requirement from %s", ui.Dim.Sprint(source))
 			}
 		}
 
@@ -108,6 +143,7 @@ func getGoVersionString(goBinary string) string {
 
 func init() {
 	currentCmd.Flags().BoolVarP(&currentPath, "path", "p", false, "Show path to Go binary")
+	currentCmd.Flags().BoolVar(&currentExplain, "explain", false, "Show the version resolution chain")
 }
 
 // findAliasesForVersion is defined in list.go but we need it here too
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/shell"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var (
+	envFormat     string
+	envDeactivate bool
+	envHook       bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [version|alias|constraint|.]",
+	Short: "Print the environment for a Go version",
+	Long: `Print the environment variables that activate a Go version, for scripts,
+CI steps and Makefiles that cannot run through 'govm exec'.
+
+The output sets GOROOT, puts the version's bin directory first in PATH
+(replacing any other govm-managed version) and sets the variables from
+matching [versions."<spec>"] sections of config.toml. Variables configured
+only for other versions are unset. Without an argument the version
+that applies to the current directory is used; '.' reads the project files
+only. Missing versions are installed if auto-install is enabled.
+
+Formats: sh (default), fish, powershell, dotenv, json, make.
+
+Examples:
+  eval "$(govm env 1.22)"                  Activate Go 1.22 in sh/bash/zsh
+  govm env 1.22 -f fish | source           Activate Go 1.22 in fish
+  govm env . -f powershell | Invoke-Expression
+  govm env . -f dotenv > .env              Write a dotenv file
+  govm env . -f make > govm.mk             For 'include govm.mk' in a Makefile
+  eval "$(govm env --deactivate)"          Undo the changes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// stdout is meant to be evaluated, keep install messages off it
+		defer ui.SetOutput(ui.SetOutput(os.Stderr))
+
+		var env shell.Env
+		if envHook {
+			// The shell hook manages GOROOT and PATH itself and only asks
+			// for the variables configured for an installed version
+			if len(args) != 1 {
+				return fmt.Errorf("--hook requires a version")
+			}
+			env = shell.ConfiguredEnv(args[0])
+		} else if envDeactivate {
+			if len(args) > 0 {
+				return fmt.Errorf("--deactivate does not take a version")
+			}
+			vars, err := shell.DeactivateEnv()
+			if err != nil {
+				return err
+			}
+			env = vars
+		} else {
+			spec := ""
+			if len(args) > 0 {
+				spec = args[0]
+			}
+			ver, err := specVersion(spec)
+			if err != nil {
+				return err
+			}
+			vars, err := shell.VersionEnv(ver)
+			if err != nil {
+				return err
+			}
+			env = vars
+		}
+
+		code, err := env.Format(envFormat)
+		if err != nil {
+			return err
+		}
+		fmt.Print(code)
+		return nil
+	},
+}
+
+// specVersion resolves the version argument of 'govm env' and 'govm exec'
+// the way 'govm use' does: aliases, partial versions and constraints, "."
+// for the project version and "" for the resolved version, installing it
+// when auto-install allows
+func specVersion(spec string) (string, error) {
+	mgr, err := version.NewManager()
+	if err != nil {
+		return "", err
+	}
+
+	switch spec {
+	case "":
+		res, err := version.Resolve("")
+		if err != nil {
+			return "", err
+		}
+		if res.Version != "" {
+			return res.Version, nil
+		}
+		return mgr.EnsureInstalled(res.Raw)
+
+	case ".":
+		ver, _, err := version.DetectVersion("")
+		if err != nil {
+			return "", err
+		}
+		return mgr.EnsureInstalled(ver)
+
+	default:
+		return mgr.EnsureInstalled(spec)
+	}
+}
+
+func init() {
+	envCmd.Flags().StringVarP(&envFormat, "format", "f", shell.FormatSh,
+		"Output format: "+strings.Join(shell.EnvFormats, ", "))
+	envCmd.Flags().BoolVar(&envDeactivate, "deactivate", false, "Print statements that undo the environment")
+	envCmd.Flags().BoolVar(&envHook, "hook", false, "Print only the configured variables, for the shell integration")
+	envCmd.Flags().MarkHidden("hook")
+}
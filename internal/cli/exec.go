@@ -1,82 +1,285 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/shell"
+	"github.com/wenzzy/govm/internal/ui"
 	"github.com/wenzzy/govm/internal/version"
 )
 
+// Cache modes of 'govm exec --cache'
+const (
+	execCacheShared  = "shared"  // GOCACHE and GOMODCACHE of the caller
+	execCacheVersion = "version" // ~/.govm/cache/exec/<version>, kept between runs
+	execCacheTemp    = "temp"    // A temporary directory, removed afterwards
+)
+
+// execOptions holds the flags of 'govm exec'. They are parsed by hand, as
+// flag parsing is disabled to leave the command's own flags alone.
+type execOptions struct {
+	hermetic bool
+	printEnv bool
+	cache    string
+}
+
 var execCmd = &cobra.Command{
-	Use:   "exec <version> <command> [args...]",
+	Use:   "exec [--hermetic] [--cache shared|version|temp] [--print-env] <version|alias|constraint|.> <command> [args...]",
 	Short: "Run a command with a specific Go version",
 	Long: `Execute a command using a specific Go version without switching globally.
 
-The specified Go version must be installed. If not, install it first with 'govm install'.
+The version is resolved like 'govm use': exact, partial ("1.22") or a
+constraint ("~1.21") picks the newest installed match, "." the version of
+the current project. A missing version is installed when auto-install is
+enabled.
+
+Options (before the version):
+  --hermetic     Start from a minimal environment: only identity, locale,
+                 terminal and proxy variables are kept (and GOCACHE,
+                 GOMODCACHE and GOPATH with --cache shared),
+                 GOTOOLCHAIN=local and GOENV=off. GOFLAGS, [versions] env
+                 and isolate settings are not applied.
+  --cache MODE   GOCACHE and GOMODCACHE to use: shared (default), version
+                 (~/.govm/cache/exec/<version>) or temp (removed afterwards)
+  --print-env    Print the effective environment instead of running. With
+                 --cache temp the directory is only created at run time,
+                 so a govm-exec-<random> placeholder is printed.
 
 Examples:
   govm exec 1.21.0 go version        Run 'go version' with Go 1.21.0
-  govm exec 1.22.0 go build ./...    Build project with Go 1.22.0
-  govm exec 1.20.0 go test -v        Run tests with Go 1.20.0
+  govm exec 1.22 go build ./...      Build with the newest installed 1.22.x
+  govm exec . go test ./...          Run tests with the project's version
+  govm exec '~1.21' go test ./...    Run tests with the newest installed 1.21.x
+  govm exec --hermetic --cache temp 1.23 go test ./...
+  govm exec --hermetic --print-env 1.23
   g exec 1.21.0 go run main.go       Short form`,
-	Args:               cobra.MinimumNArgs(2),
+	Args:               cobra.MinimumNArgs(1),
 	DisableFlagParsing: true, // Allow flags to be passed to the subcommand
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ver := config.ResolveVersion(args[0])
-		command := args[1:]
-
-		mgr, err := version.NewManager()
+		opts, args, err := parseExecOptions(args)
 		if err != nil {
 			return err
 		}
+		if opts.cache == "help" {
+			return cmd.Help()
+		}
+		if len(args) == 0 || (len(args) < 2 && !opts.printEnv) {
+			return fmt.Errorf("usage: govm exec [options] <version> <command> [args...]")
+		}
+		return execWithVersion(args[0], args[1:], opts)
+	},
+}
+
+// parseExecOptions parses the options in front of the version and returns
+// the remaining arguments
+func parseExecOptions(args []string) (execOptions, []string, error) {
+	opts := execOptions{cache: execCacheShared}
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		arg := args[0]
+		args = args[1:]
 
-		// Check if version is installed
-		if !mgr.IsInstalled(ver) {
-			return fmt.Errorf("version %s is not installed. Run 'govm install %s' first", ver, ver)
+		switch {
+		case arg == "--":
+			return opts, args, nil
+		case arg == "-h" || arg == "--help":
+			opts.cache = "help"
+			return opts, args, nil
+		case arg == "--hermetic":
+			opts.hermetic = true
+		case arg == "--print-env":
+			opts.printEnv = true
+		case arg == "--cache" || strings.HasPrefix(arg, "--cache="):
+			value, ok := strings.CutPrefix(arg, "--cache=")
+			if !ok {
+				if len(args) == 0 {
+					return opts, nil, fmt.Errorf("--cache needs a value (shared, version or temp)")
+				}
+				value, args = args[0], args[1:]
+			}
+			if value != execCacheShared && value != execCacheVersion && value != execCacheTemp {
+				return opts, nil, fmt.Errorf("invalid --cache %q (use shared, version or temp)", value)
+			}
+			opts.cache = value
+		default:
+			return opts, nil, fmt.Errorf("unknown option %s", arg)
 		}
+	}
+	return opts, args, nil
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run [--] <command> [args...]",
+	Short: "Run a command with the project's Go version",
+	Long: `Run a command with the Go version of the current project, detected from
+.go-version, .tool-versions, go.work or go.mod, without changing the
+current version. Same as 'govm exec . <command>'.
 
-		// Get the Go binary path for this version
-		goBinary, err := mgr.GetGoBinary(ver)
+Examples:
+  govm run -- go test ./...          Test with the project's version
+  govm run go build -o app .         The -- is optional`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return execWithVersion(".", args, execOptions{cache: execCacheShared})
+	},
+}
+
+// execWithVersion runs command with the version spec resolves to
+func execWithVersion(spec string, command []string, opts execOptions) error {
+	mgr, err := version.NewManager()
+	if err != nil {
+		return err
+	}
+
+	// The command owns stdout, keep detection and install messages off it
+	prev := ui.SetOutput(os.Stderr)
+	ver, err := specVersion(spec)
+	ui.SetOutput(prev)
+	if err != nil {
+		return err
+	}
+
+	// Get the Go binary path for this version
+	goBinary, err := mgr.GetGoBinary(ver)
+	if err != nil {
+		return err
+	}
+
+	cacheDir := ""
+	switch opts.cache {
+	case execCacheVersion:
+		paths, err := config.GetPaths()
 		if err != nil {
 			return err
 		}
+		cacheDir = filepath.Join(paths.Cache, "exec", ver)
+	case execCacheTemp:
+		if opts.printEnv {
+			// Nothing runs, so show where the directory would go
+			cacheDir = filepath.Join(os.TempDir(), "govm-exec-<random>")
+		} else if cacheDir, err = os.MkdirTemp("", "govm-exec-"); err != nil {
+			return err
+		}
+	}
 
-		// Get the bin directory for this version
-		binDir := filepath.Dir(goBinary)
-
-		// Prepare environment
-		env := os.Environ()
-		env = updateEnv(env, "GOROOT", filepath.Dir(binDir))
-		env = prependPath(env, binDir)
-
-		// If the command is "go", use the specific binary
-		cmdName := command[0]
-		cmdArgs := command[1:]
-
-		if cmdName == "go" {
-			cmdName = goBinary
-		} else {
-			// Check if the command exists in the version's bin directory
-			versionBinCmd := filepath.Join(binDir, cmdName)
-			if _, err := os.Stat(versionBinCmd); err == nil {
-				cmdName = versionBinCmd
-			}
+	// Prepare environment (same variables 'govm env' prints, or a
+	// hermetic one)
+	var vars shell.Env
+	var environ []string
+	if opts.hermetic {
+		if vars, err = shell.HermeticEnv(ver, cacheDir); err != nil {
+			return err
 		}
+		environ = vars.Apply(nil)
+	} else {
+		if vars, err = shell.VersionEnv(ver); err != nil {
+			return err
+		}
+		if cacheDir != "" {
+			vars.Set("GOCACHE", filepath.Join(cacheDir, "build"))
+			vars.Set("GOMODCACHE", filepath.Join(cacheDir, "mod"))
+		}
+		environ = vars.Apply(os.Environ())
+	}
 
-		// Execute the command
-		return executeCommand(cmdName, cmdArgs, env)
-	},
+	if opts.printEnv {
+		sort.Strings(environ)
+		for _, e := range environ {
+			fmt.Println(e)
+		}
+		return nil
+	}
+
+	name := versionCommand(goBinary, command[0])
+	if opts.cache == execCacheTemp {
+		// The cache has to be removed afterwards, so run as a child
+		code := runAndCleanup(goBinary, name, command[1:], environ, cacheDir)
+		os.Exit(code)
+	}
+
+	// Execute the command
+	return executeCommand(name, command[1:], environ)
+}
+
+// runAndCleanup runs a command, removes its temporary cache directory and
+// returns the command's exit code
+func runAndCleanup(goBinary, name string, args, environ []string, cacheDir string) int {
+	c := exec.Command(name, args...)
+	c.Env = environ
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	code := 0
+	var exitErr *exec.ExitError
+	if err := c.Run(); errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		ui.PrintError("%s", err)
+		code = 1
+	}
+
+	// The module cache is read-only, let go remove it
+	clean := exec.Command(goBinary, "clean", "-modcache")
+	clean.Env = environ
+	_ = clean.Run()
+	if err := os.RemoveAll(cacheDir); err != nil {
+		ui.PrintWarning("Failed to remove %s: %s", cacheDir, err)
+	}
+	return code
+}
+
+// versionExecCmd prepares command to run with a version in the environment
+// 'govm exec' uses
+func versionExecCmd(mgr *version.Manager, ver string, command []string) (*exec.Cmd, error) {
+	goBinary, err := mgr.GetGoBinary(ver)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := shell.VersionEnv(ver)
+	if err != nil {
+		return nil, err
+	}
+
+	c := exec.Command(versionCommand(goBinary, command[0]), command[1:]...)
+	c.Env = vars.Apply(os.Environ())
+	return c, nil
+}
+
+// versionCommand returns what to run for name with a version: its go
+// binary for "go", a tool from its bin directory, or name itself
+func versionCommand(goBinary, name string) string {
+	if name == "go" {
+		return goBinary
+	}
+
+	// Check if the command exists in the version's bin directory
+	versionBinCmd := filepath.Join(filepath.Dir(goBinary), name)
+	if _, err := os.Stat(versionBinCmd); err == nil {
+		return versionBinCmd
+	}
+	return name
 }
 
 // executeCommand runs a command with the given environment
 func executeCommand(name string, args []string, env []string) error {
 	// Try to use syscall.Exec for a cleaner process replacement
 	// This replaces the current process with the new command
+	// Resolve the command against the PATH it will run with
+	for _, e := range env {
+		if strings.HasPrefix(e, "PATH=") {
+			os.Setenv("PATH", e[5:])
+		}
+	}
 	binary, err := exec.LookPath(name)
 	if err != nil {
 		return fmt.Errorf("command not found: %s", name)
@@ -101,13 +304,7 @@ func updateEnv(env []string, key, value string) []string {
 	return append(env, key+"="+value)
 }
 
-// prependPath prepends a directory to the PATH environment variable
-func prependPath(env []string, dir string) []string {
-	for i, e := range env {
-		if len(e) > 5 && e[:5] == "PATH=" {
-			env[i] = "PATH=" + dir + string(os.PathListSeparator) + e[5:]
-			return env
-		}
-	}
-	return append(env, "PATH="+dir)
+func init() {
+	// Everything after the command belongs to it
+	runCmd.Flags().SetInterspersed(false)
 }
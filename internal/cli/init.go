@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/wenzzy/govm/internal/config"
@@ -56,6 +57,22 @@ Examples:
 		} else {
 			fmt.Println(`export GOVM_INHERIT_VERSION="false"`)
 		}
+		fmt.Printf("export GOVM_INHERIT_STOP=%s\n", shell.Quote(strings.Join(cfg.InheritStop, ",")))
+		fmt.Printf("export GOVM_INHERIT_MAX_DEPTH=%d\n", cfg.InheritDepth)
+		fmt.Printf("export GOVM_CEILING_DIRECTORIES=%s\n", shell.Quote(strings.Join(cfg.CeilingDirs(), ":")))
+		if cfg.SwitchScope == config.ScopeSession {
+			fmt.Println(`export GOVM_SWITCH_SCOPE="session"`)
+		} else {
+			fmt.Println(`export GOVM_SWITCH_SCOPE="global"`)
+		}
+		fmt.Printf("export GOVM_ON_LEAVE=%s\n", shell.Quote(cfg.OnLeave))
+		fmt.Printf("export GOVM_ISOLATE=%s\n", shell.Quote(strings.Join(cfg.Isolate, ",")))
+		// Per-version variables need govm itself, only call it when configured
+		if len(cfg.Versions) > 0 {
+			fmt.Println(`export GOVM_VERSION_ENV="true"`)
+		} else {
+			fmt.Println(`export GOVM_VERSION_ENV="false"`)
+		}
 
 		// Output the shell code (will be eval'd)
 		fmt.Print(code)
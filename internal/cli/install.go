@@ -1,25 +1,41 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
 	"github.com/wenzzy/govm/internal/ui"
 	"github.com/wenzzy/govm/internal/version"
 )
 
 var (
-	installDefault bool
+	installDefault   bool
+	installToolsFrom string
 )
 
 var installCmd = &cobra.Command{
-	Use:     "install <version>",
+	Use:     "install <version|constraint>",
 	Aliases: []string{"i", "add"},
 	Short:   "Install a Go version",
 	Long: `Install a specific Go version.
 
+Partial versions and constraints (~1.21, ^1.22, '>=1.21 <1.23', 1.22.x) are
+resolved by version order, against installed versions first and then
+against the go.dev release index.
+
+The tools of the [tools] manifest in config.toml are installed into every
+new version (see 'govm tools'). --reinstall-tools-from also installs the
+tools built by an existing version. A tool that fails to install is
+reported but does not fail the install.
+
 Examples:
   govm install 1.22.0         Install Go 1.22.0
   govm install 1.22.0 -d      Install and set as default
   govm install latest         Install the latest stable version
+  govm install '~1.21'        Install the newest 1.21.x
+  govm install 1.23 --reinstall-tools-from 1.22
+                              Install and copy the tool set of Go 1.22
   g i 1.21.0                  Short form`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -40,10 +56,67 @@ Examples:
 			ver = latestVer
 		}
 
-		return mgr.Install(ver, installDefault, true)
+		// Read the tool set before installing, so a bad source fails early
+		var tools []version.Tool
+		if installToolsFrom != "" {
+			source, err := mgr.ResolveInstalled(config.ResolveVersion(installToolsFrom))
+			if err != nil {
+				return err
+			}
+			if len(source) == 0 {
+				return fmt.Errorf("version %s is not installed", installToolsFrom)
+			}
+			if tools, err = mgr.InstalledTools(source[0]); err != nil {
+				return err
+			}
+			if len(tools) == 0 {
+				dir, _ := mgr.ToolsDir(source[0])
+				ui.PrintWarning("No tools built by Go %s found in %s", source[0], dir)
+			}
+		}
+
+		if err := mgr.Install(ver, installDefault, true); err != nil {
+			return err
+		}
+
+		if len(tools) > 0 {
+			return reinstallTools(mgr, ver, tools)
+		}
+		return nil
 	},
 }
 
+// reinstallTools installs tools copied from another version into the
+// version just installed, skipping those the manifest already covered
+func reinstallTools(mgr *version.Manager, spec string, tools []version.Tool) error {
+	installed, err := mgr.ResolveInstalled(config.ResolveVersion(spec))
+	if err != nil || len(installed) == 0 {
+		return fmt.Errorf("failed to locate the installed version %s", spec)
+	}
+	ver := installed[0]
+
+	manifest := make(map[string]bool)
+	for _, t := range version.ManifestTools() {
+		manifest[t.Path] = true
+	}
+	var pending []version.Tool
+	for _, t := range tools {
+		if !manifest[t.Path] {
+			pending = append(pending, t)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ui.PrintInfo("Reinstalling %d tool(s) for Go %s...", len(pending), ver)
+	if failed := version.FailedTools(mgr.InstallTools(ver, pending)); len(failed) > 0 {
+		ui.PrintWarning("%d of %d tool(s) failed to install", len(failed), len(pending))
+	}
+	return nil
+}
+
 func init() {
 	installCmd.Flags().BoolVarP(&installDefault, "default", "d", false, "Set as default version after install")
+	installCmd.Flags().StringVar(&installToolsFrom, "reinstall-tools-from", "", "Also install the tools built by an installed version")
 }
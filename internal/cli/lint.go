@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/lint"
+	"github.com/wenzzy/govm/internal/ui"
+)
+
+// Output formats of 'govm lint'
+const (
+	lintText  = "text"
+	lintJSON  = "json"
+	lintSARIF = "sarif"
+)
+
+var lintFormat string
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [dir]",
+	Short: "Check CI configs and Dockerfiles against the project Go version",
+	Long: `Compare the Go versions declared across a repository with the one the
+module requires, and report every place that drifted with file and line.
+
+The requirement comes from go.work, else go.mod (its toolchain line when
+newer), else .go-version. Checked against it are:
+  .go-version                 the pinned version
+  .github/workflows/*.yml     actions/setup-go go-version inputs (including
+                              matrix lists and ${{ env.X }} / ${{ matrix.X }}
+                              references) and golang:<tag> images
+  Dockerfile, *.Dockerfile    FROM golang:<tag>, also through ARG defaults
+  .gitlab-ci.yml              golang:<tag> images, also through variables
+
+A version older than the requirement is an error; one on another minor
+line is a warning. Values such as "stable" are skipped. The command exits
+non-zero if anything is reported.
+
+Examples:
+  govm lint                           Lint the current repository
+  govm lint ~/src/api                 Lint another repository
+  govm lint --format sarif > govm.sarif   For GitHub code scanning`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		if lintFormat != lintText && lintFormat != lintJSON && lintFormat != lintSARIF {
+			return fmt.Errorf("invalid --format %q (use text, json or sarif)", lintFormat)
+		}
+
+		report, err := lint.Check(dir)
+		if err != nil {
+			return err
+		}
+
+		switch lintFormat {
+		case lintJSON:
+			err = lint.WriteJSON(os.Stdout, report)
+		case lintSARIF:
+			err = lint.WriteSARIF(os.Stdout, report)
+		default:
+			printLintReport(report)
+		}
+		if err != nil {
+			return err
+		}
+
+		if n := len(report.Findings); n > 0 {
+			return fmt.Errorf("%d Go version problem(s) found", n)
+		}
+		return nil
+	},
+}
+
+// printLintReport prints every declaration with its status, then the findings
+func printLintReport(r *lint.Report) {
+	ui.PrintHeader("Go versions in " + r.Root)
+
+	status := make(map[string]lint.Finding)
+	for _, f := range r.Findings {
+		status[f.Location()+":"+f.Version] = f
+	}
+
+	table := ui.NewTable("Location", "Kind", "Version", "Status")
+	for i, d := range r.Declarations {
+		state := ui.Green.Sprint("ok")
+		if i == 0 {
+			state = ui.Dim.Sprint("required")
+		} else if f, ok := status[d.Location()+":"+d.Version]; ok {
+			if f.Level == lint.LevelError {
+				state = ui.Error.Sprint("too old")
+			} else {
+				state = ui.Warning.Sprint("differs")
+			}
+		}
+		table.AddRow(d.Location(), d.Kind, d.Version, state)
+	}
+	table.Render()
+
+	ui.Println()
+	if len(r.Findings) == 0 {
+		ui.PrintSuccess("%d declaration(s) agree with Go %s (%s)", len(r.Declarations), r.Reference.Version, r.Reference.Location())
+		return
+	}
+	for _, f := range r.Findings {
+		if f.Level == lint.LevelError {
+			ui.PrintError("%s: %s", f.Location(), f.Message)
+		} else {
+			ui.PrintWarning("%s: %s", f.Location(), f.Message)
+		}
+	}
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFormat, "format", lintText, "Output format: text, json or sarif")
+}
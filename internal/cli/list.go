@@ -159,9 +159,16 @@ func listRemoteVersions() error {
 
 func findAliasesForVersion(ver string) []string {
 	aliases := config.ListAliases()
+
+	var installed []string
+	if mgr, err := version.NewManager(); err == nil {
+		installed, _ = mgr.ListInstalled()
+	}
+
 	var result []string
-	for name, version := range aliases {
-		if version == ver {
+	for name, target := range aliases {
+		// Constraint aliases point at their newest installed match
+		if target == ver || (version.IsConstraint(target) && version.SelectInstalled(target, installed) == ver) {
 			result = append(result, name)
 		}
 	}
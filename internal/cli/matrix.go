@@ -0,0 +1,378 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var (
+	matrixParallel int
+	matrixJUnit    string
+	matrixJSON     string
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix <version|constraint|supported|go.mod>... -- <command> [args...]",
+	Short: "Run a command across several Go versions",
+	Long: `Run a command with every given Go version and summarize the results.
+
+Versions can be exact, partial or constraints; each matching minor line
+runs once, with its newest installed patch or else its newest release.
+Two keywords are accepted as well:
+  supported   the two newest minor lines (the supported releases)
+  go.mod      every minor line from the go directive of go.mod through the latest
+
+Missing versions are installed first when auto-install is enabled. Output
+is streamed with a [version] prefix. With --parallel each version builds
+into its own GOCACHE under ~/.govm/cache/gocache so concurrent runs do not
+contend for one cache. The command exits non-zero if any version fails.
+
+Examples:
+  govm matrix 1.21 1.22 1.23 -- go test ./...
+  govm matrix supported -- go test ./...
+  govm matrix go.mod -p 4 -- go test -race ./...
+  govm matrix '>=1.21' --junit matrix.xml -- go test ./...`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dash := cmd.ArgsLenAtDash()
+		if dash < 1 || dash == len(args) {
+			return fmt.Errorf("usage: govm matrix <version>... -- <command> [args...]")
+		}
+		specs, command := args[:dash], args[dash:]
+		if matrixParallel < 1 {
+			return fmt.Errorf("--parallel must be at least 1")
+		}
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		spinner := ui.NewSpinner("Resolving versions...")
+		spinner.Start()
+		versions, err := mgr.ExpandMatrix(specs)
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+
+		ui.PrintInfo("Running '%s' with Go %s", strings.Join(command, " "), strings.Join(versions, ", "))
+
+		// Install missing versions one at a time before running anything
+		results := make([]*matrixResult, len(versions))
+		for i, ver := range versions {
+			results[i] = &matrixResult{Version: ver}
+			if !mgr.IsInstalled(ver) {
+				if _, err := mgr.EnsureInstalled(ver); err != nil {
+					results[i].Err = err
+				}
+			}
+		}
+
+		runMatrix(mgr, results, command, matrixJUnit != "" || matrixJSON != "")
+		printMatrixSummary(results)
+
+		if matrixJSON != "" {
+			if err := writeMatrixJSON(matrixJSON, command, results); err != nil {
+				return err
+			}
+			ui.PrintInfo("JSON report written to %s", matrixJSON)
+		}
+		if matrixJUnit != "" {
+			if err := writeMatrixJUnit(matrixJUnit, command, results); err != nil {
+				return err
+			}
+			ui.PrintInfo("JUnit report written to %s", matrixJUnit)
+		}
+
+		failed := 0
+		for _, r := range results {
+			if r.Status() != matrixPassed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d version(s) failed", failed, len(results))
+		}
+		ui.PrintSuccess("All %d version(s) passed", len(results))
+		return nil
+	},
+}
+
+// Status of a matrix run
+const (
+	matrixPassed = "passed"
+	matrixFailed = "failed" // The command exited non-zero
+	matrixError  = "error"  // The command could not be run
+)
+
+// matrixResult is the outcome of running the command with one version
+type matrixResult struct {
+	Version  string
+	ExitCode int
+	Duration time.Duration
+	Err      error
+	Output   bytes.Buffer // Only filled when a report is written
+}
+
+// Status returns matrixPassed, matrixFailed or matrixError
+func (r *matrixResult) Status() string {
+	switch {
+	case r.Err != nil:
+		return matrixError
+	case r.ExitCode != 0:
+		return matrixFailed
+	default:
+		return matrixPassed
+	}
+}
+
+// runMatrix runs command for every result that is not already failed,
+// at most matrixParallel at a time
+func runMatrix(mgr *version.Manager, results []*matrixResult, command []string, capture bool) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Version))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, matrixParallel)
+
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(r *matrixResult) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			out := &prefixWriter{
+				mu:     &mu,
+				out:    os.Stdout,
+				prefix: ui.Dim.Sprintf("%-*s ", width+2, "["+r.Version+"]"),
+			}
+			var w io.Writer = out
+			if capture {
+				w = io.MultiWriter(out, &r.Output)
+			}
+			runMatrixVersion(mgr, r, command, w)
+			out.Flush()
+		}(r)
+	}
+	wg.Wait()
+}
+
+// runMatrixVersion runs command with the result's version, writing its
+// output to w
+func runMatrixVersion(mgr *version.Manager, r *matrixResult, command []string, w io.Writer) {
+	c, err := versionExecCmd(mgr, r.Version, command)
+	if err != nil {
+		r.Err = err
+		return
+	}
+	if matrixParallel > 1 {
+		if paths, err := config.GetPaths(); err == nil {
+			c.Env = updateEnv(c.Env, "GOCACHE", filepath.Join(paths.Cache, "gocache", r.Version))
+		}
+	}
+	c.Stdout = w
+	c.Stderr = w
+
+	start := time.Now()
+	err = c.Run()
+	r.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		r.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		r.Err = err
+	}
+}
+
+// printMatrixSummary prints the status and duration of every version
+func printMatrixSummary(results []*matrixResult) {
+	ui.PrintHeader("Matrix")
+
+	table := ui.NewTable("Version", "Duration", "Status")
+	for _, r := range results {
+		duration := "-"
+		if r.Duration > 0 {
+			duration = r.Duration.Round(10 * time.Millisecond).String()
+		}
+
+		var status string
+		switch r.Status() {
+		case matrixPassed:
+			status = ui.Green.Sprint("passed")
+		case matrixFailed:
+			status = ui.Warning.Sprintf("failed (exit %d)", r.ExitCode)
+		default:
+			status = ui.Warning.Sprintf("error: %s", r.Err)
+		}
+		table.AddRow(r.Version, duration, status)
+	}
+	table.Render()
+	ui.Println()
+}
+
+// prefixWriter writes complete lines to out with a prefix, so the output of
+// concurrent runs does not interleave within a line
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a final line that has no newline
+func (w *prefixWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s\n", w.prefix, line)
+}
+
+// matrixJSONResult is one version in the JSON report
+type matrixJSONResult struct {
+	Version  string  `json:"version"`
+	Status   string  `json:"status"`
+	ExitCode int     `json:"exit_code"`
+	Duration float64 `json:"duration_seconds"`
+	Error    string  `json:"error,omitempty"`
+	Output   string  `json:"output"`
+}
+
+// writeMatrixJSON writes the results as JSON
+func writeMatrixJSON(path string, command []string, results []*matrixResult) error {
+	report := struct {
+		Command []string           `json:"command"`
+		Results []matrixJSONResult `json:"results"`
+	}{Command: command}
+
+	for _, r := range results {
+		jr := matrixJSONResult{
+			Version:  r.Version,
+			Status:   r.Status(),
+			ExitCode: r.ExitCode,
+			Duration: r.Duration.Seconds(),
+			Output:   r.Output.String(),
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		report.Results = append(report.Results, jr)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// JUnit XML report, one test case per version
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeMatrixJUnit writes the results as a JUnit XML report
+func writeMatrixJUnit(path string, command []string, results []*matrixResult) error {
+	suite := junitSuite{Name: strings.Join(command, " "), Tests: len(results)}
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+		tc := junitCase{
+			Name:      "go" + r.Version,
+			Classname: "govm.matrix",
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		}
+		switch r.Status() {
+		case matrixFailed:
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: fmt.Sprintf("exit status %d", r.ExitCode), Text: r.Output.String()}
+		case matrixError:
+			suite.Errors++
+			tc.Error = &junitFailure{Message: r.Err.Error(), Text: r.Output.String()}
+		default:
+			tc.SystemOut = r.Output.String()
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	data, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0644)
+}
+
+func init() {
+	matrixCmd.Flags().IntVarP(&matrixParallel, "parallel", "p", 1, "Number of versions to run at once")
+	matrixCmd.Flags().StringVar(&matrixJUnit, "junit", "", "Write a JUnit XML report to `file`")
+	matrixCmd.Flags().StringVar(&matrixJSON, "json", "", "Write a JSON report to `file`")
+}
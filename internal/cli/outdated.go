@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List installed Go versions with newer patch releases",
+	Long: `List every installed minor line whose newest installed patch is behind
+the newest stable release of that line. Lines with a pending security
+release are marked.
+
+Examples:
+  govm outdated               Show outdated minor lines
+  govm update                 Install the newer patches`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		spinner := ui.NewSpinner("Checking for patch releases...")
+		spinner.Start()
+		outdated, err := mgr.ListOutdated()
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+
+		if len(outdated) == 0 {
+			ui.PrintSuccess("All installed minor lines are up to date")
+			return nil
+		}
+
+		ui.PrintHeader("Outdated Go Versions")
+
+		table := ui.NewTable("Line", "Installed", "Latest", "Notes")
+		security := 0
+		for _, o := range outdated {
+			notes := ""
+			if len(o.Security) > 0 {
+				notes = ui.Warning.Sprintf("security: %s", strings.Join(o.Security, ", "))
+				security++
+			}
+			table.AddRow(o.Minor, o.Installed, o.Latest, notes)
+		}
+		table.Render()
+
+		ui.Println()
+		if security > 0 {
+			ui.PrintWarning("%d line(s) are missing security releases", security)
+		}
+		ui.PrintHint("Run 'govm update' to install the latest patches")
+		return nil
+	},
+}
+
+// outdatedLines filters outdated lines to the requested minor lines
+// ("1.22", or a version such as "1.22.3" standing for its line)
+func outdatedLines(outdated []version.Outdated, args []string) []version.Outdated {
+	if len(args) == 0 {
+		return outdated
+	}
+
+	var result []version.Outdated
+	for _, arg := range args {
+		minor := version.MinorLine(config.NormalizeVersion(arg))
+		if minor == "" {
+			minor = arg
+		}
+
+		found := false
+		for _, o := range outdated {
+			if o.Minor == minor {
+				result = append(result, o)
+				found = true
+				break
+			}
+		}
+		if !found {
+			ui.PrintInfo("Go %s is up to date or not installed", minor)
+		}
+	}
+	return result
+}
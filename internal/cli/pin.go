@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [version|alias]",
+	Short: "Pin a Go version for the current directory",
+	Long: `Write a .go-version file in the current directory.
+
+.go-version takes precedence over .tool-versions, go.work and go.mod,
+so it can pin directories that are not Go modules (scripts, tooling)
+or override a module's go directive. Aliases and partial versions are
+resolved to an installed version before writing, because aliases are local
+to your machine and a pin should not move when patches are installed.
+Constraints cannot be pinned. Without an argument, the active version is
+pinned.
+
+Examples:
+  govm pin 1.22.5             Pin Go 1.22.5
+  govm pin 1.22               Pin the newest installed Go 1.22.x
+  govm pin                    Pin the active version`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		var ver string
+		if len(args) == 1 {
+			if ver, err = pinVersion(mgr, args[0]); err != nil {
+				return err
+			}
+		} else {
+			// The session version, unless project files outrank it
+			if res, _ := version.Resolve(""); res != nil && res.Source == version.SourceSession {
+				ver = res.Raw
+			}
+			if ver == "" {
+				ver, _ = mgr.Current()
+			}
+			if ver == "" {
+				return fmt.Errorf("no Go version is active, specify a version to pin")
+			}
+		}
+
+		if ver == "" || ver[0] < '0' || ver[0] > '9' {
+			return fmt.Errorf("invalid version: %s", ver)
+		}
+
+		if err := os.WriteFile(version.GoVersionFile, []byte(ver+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", version.GoVersionFile, err)
+		}
+
+		wd, _ := os.Getwd()
+		ui.PrintSuccess("Pinned Go %s in %s", ver, filepath.Join(wd, version.GoVersionFile))
+		ui.PrintHint("Run 'govm use .' to switch now")
+		return nil
+	},
+}
+
+// pinVersion resolves an alias or partial version to the newest installed
+// match. .go-version readers only understand single versions, so
+// constraints are rejected.
+func pinVersion(mgr *version.Manager, spec string) (string, error) {
+	ver := config.NormalizeVersion(config.ResolveVersion(spec))
+	if version.IsConstraint(ver) {
+		return "", fmt.Errorf("cannot pin constraint %s: .go-version holds a single version", spec)
+	}
+	if len(strings.Split(ver, ".")) >= 3 {
+		return ver, nil
+	}
+
+	matched, err := mgr.ResolveInstalled(ver)
+	if err != nil {
+		return "", err
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("no installed version matches %s (run 'govm install %s' first)", spec, spec)
+	}
+	return matched[0], nil
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Remove the .go-version pin from the current directory",
+	Long: `Remove the .go-version file from the current directory.
+
+Examples:
+  govm unpin                  Remove the pin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := os.Remove(version.GoVersionFile); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no %s in the current directory", version.GoVersionFile)
+			}
+			return err
+		}
+
+		ui.PrintSuccess("Removed %s", version.GoVersionFile)
+		return nil
+	},
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var rehashCmd = &cobra.Command{
+	Use:   "rehash",
+	Short: "Regenerate shims in ~/.govm/bin",
+	Long: `Regenerate the go, gofmt and toolchain tool shims in ~/.govm/bin.
+
+A shim resolves the Go version on every call (session version, project
+files, default_version, then the global version) and runs the real binary.
+This gives project-aware versions to IDEs, Makefiles, cron jobs and git
+hooks that do not load the shell integration. Shims are refreshed
+automatically on install and uninstall.
+
+Examples:
+  govm rehash                  Regenerate shims`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		if err := mgr.Rehash(); err != nil {
+			return err
+		}
+
+		paths, err := config.GetPaths()
+		if err != nil {
+			return err
+		}
+		ui.PrintSuccess("Shims updated in %s", paths.Bin)
+		return nil
+	},
+}
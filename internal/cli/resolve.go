@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve [dir]",
+	Short: "Explain which Go version applies to a directory",
+	Long: `List every version source for a directory in precedence order and show
+which one decides the Go version:
+
+  1. session         GOVM_SHELL_VERSION ('govm shell')
+  2. project files   .go-version, .tool-versions, go.work, go.mod
+                     (parent directories too with inherit_version = true,
+                     up to the repository root, $HOME, inherit_max_depth
+                     or a ceiling directory)
+  3. GOTOOLCHAIN     a pin, or a minimum on top of the project files
+  4. session         GOVM_AUTO_VERSION, the version the session-scope
+                     auto-switch hook last activated
+  5. default_version
+  6. global          the ~/.govm/current symlink
+
+For each source the file or setting, the raw value, its alias or
+constraint expansion and the installed version it selects are shown.
+Shims and 'govm env' use the same rules; the shell hooks apply the
+session and project steps on every cd.
+
+Examples:
+  govm resolve                Explain the current directory
+  govm resolve ~/src/api      Explain another directory
+  govm current --explain      Same as 'govm resolve' for the current directory`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := ""
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		return explainResolution(dir)
+	},
+}
+
+// explainResolution prints the resolution chain for dir
+func explainResolution(dir string) error {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", dir)
+	}
+
+	candidates, err := version.Explain(dir)
+	if err != nil {
+		return err
+	}
+
+	ui.PrintHeader("Version resolution for " + dir)
+
+	sourceWidth, originWidth := 0, 0
+	for _, c := range candidates {
+		sourceWidth = max(sourceWidth, len(candidateSource(c)))
+		originWidth = max(originWidth, len(c.Origin))
+	}
+
+	var selected *version.Candidate
+	hasProject := false
+	for i, c := range candidates {
+		if c.Source == version.SourceProject && c.Origin != version.EnvGoToolchain {
+			hasProject = true
+		}
+
+		marker := "  "
+		if c.Selected {
+			marker = ui.Green.Sprint(ui.SymbolArrow) + " "
+			selected = &candidates[i]
+		}
+
+		source := fmt.Sprintf("%-*s", sourceWidth, candidateSource(c))
+		origin := fmt.Sprintf("%-*s", originWidth, c.Origin)
+		if c.Selected {
+			source = ui.Bold.Sprint(source)
+		} else {
+			source = ui.Dim.Sprint(source)
+		}
+
+		fmt.Printf("%s%s  %s  %s\n", marker, source, ui.Path.Sprint(origin), candidateValue(c))
+	}
+
+	if !hasProject {
+		ui.Println()
+		ui.PrintInfo("No .go-version, .tool-versions, go.work or go.mod declares a version here")
+	}
+
+	ui.Println()
+	if selected == nil {
+		ui.PrintWarning("No Go version applies")
+		ui.PrintHint("Run 'govm use <version>' to activate a version")
+		return nil
+	}
+
+	if selected.Version == "" {
+		ui.PrintWarning("Go %s is required (from %s) but not installed", selected.Raw, selected.Origin)
+		ui.PrintHint("Run 'govm install %s' or 'govm use .' to install it", selected.Raw)
+		return nil
+	}
+	ui.PrintKeyValue("Resolved", ui.GreenBold.Sprint(selected.Version))
+
+	// Compare with what this shell actually runs: its session version, which
+	// the hook may have set for another directory, or the global symlink
+	if selected.Source == version.SourceSession {
+		return nil
+	}
+	active, _ := version.SessionVersion()
+	if active == "" {
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+		active, _ = mgr.Current()
+	}
+	if active == "" || active == selected.Version {
+		return nil
+	}
+
+	ui.PrintKeyValue("Active", active)
+	if selected.Source == version.SourceProject {
+		ui.PrintHint("Run 'govm use .' to switch (the shell hook switches on cd)")
+	} else {
+		ui.PrintInfo("Outside projects the shell keeps the global version; shims and 'govm env' use default_version")
+		ui.PrintHint("Run 'govm use %s' to switch the shell too", selected.Version)
+	}
+	return nil
+}
+
+// candidateSource labels a candidate in the resolution chain
+func candidateSource(c version.Candidate) string {
+	if c.Origin == version.EnvGoToolchain {
+		return "gotoolchain"
+	}
+	return c.Source
+}
+
+// candidateValue describes a candidate's raw value and what it resolves to
+func candidateValue(c version.Candidate) string {
+	if c.Raw == "" {
+		if c.Detail != "" {
+			return ui.Dim.Sprint(c.Detail)
+		}
+		return ui.Dim.Sprint("not set")
+	}
+
+	parts := []string{c.Raw}
+	if c.Expanded != "" {
+		parts = append(parts, c.Expanded)
+	}
+	if c.Version != "" {
+		if c.Version != parts[len(parts)-1] {
+			parts = append(parts, ui.Green.Sprint(c.Version))
+		}
+	} else {
+		parts = append(parts, ui.Warning.Sprint("not installed"))
+	}
+
+	value := strings.Join(parts, " "+ui.SymbolArrow+" ")
+	if c.Detail != "" {
+		value += ui.Dim.Sprintf("  (%s)", c.Detail)
+	}
+	return value
+}
@@ -39,11 +39,27 @@ func init() {
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(useCmd)
+	rootCmd.AddCommand(shellCmd)
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(aliasCmd)
+	rootCmd.AddCommand(toolsCmd)
 	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(matrixCmd)
+	rootCmd.AddCommand(bisectCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(envCmd)
 	rootCmd.AddCommand(currentCmd)
+	rootCmd.AddCommand(resolveCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(bumpCmd)
 	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(rehashCmd)
 	rootCmd.AddCommand(upgradeCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
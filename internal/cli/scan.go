@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/scan"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var scanInstall bool
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [dir...]",
+	Short: "Find Go projects under directories and the versions they need",
+	Long: `Walk directory trees and list every project with a version file
+(.go-version, .tool-versions, go.work or go.mod), the version it requires
+and the installed version that satisfies it.
+
+Directories ignored by a .gitignore are skipped, and so are .git, vendor,
+node_modules and testdata. Each directory is checked on its own, as
+'govm use .' does there, so every module of a workspace is listed.
+
+--install installs every missing version in one go, so a freshly cloned
+set of repositories does not stall on auto-install at each cd.
+
+Examples:
+  govm scan                   Scan the current directory
+  govm scan ~/src ~/work      Scan several trees
+  govm scan ~/src --install   Install every version the projects need`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+		installed, err := mgr.ListInstalled()
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		seen := make(map[string]bool)
+		for _, root := range args {
+			spinner := ui.NewSpinner(fmt.Sprintf("Scanning %s...", root))
+			spinner.Start()
+			projects, err := scan.Projects(root)
+			spinner.Stop()
+			if err != nil {
+				return err
+			}
+
+			abs, _ := filepath.Abs(root)
+			ui.PrintHeader("Projects in " + abs)
+			if len(projects) == 0 {
+				ui.PrintInfo("No Go projects found")
+				continue
+			}
+
+			table := ui.NewTable("Project", "Source", "Requires", "Installed")
+			for _, p := range projects {
+				status := version.SelectInstalled(p.Version, installed)
+				if status == "" {
+					status = ui.Warning.Sprint("missing")
+					if spec := config.ResolveVersion(p.Version); !seen[spec] {
+						seen[spec] = true
+						missing = append(missing, p.Version)
+					}
+				} else {
+					status = ui.Green.Sprint(status)
+				}
+				table.AddRow(scanProjectName(abs, p.Dir), filepath.Base(p.Source), p.Version, status)
+			}
+			table.Render()
+		}
+
+		ui.Println()
+		if len(missing) == 0 {
+			ui.PrintSuccess("Every required version is installed")
+			return nil
+		}
+		if !scanInstall {
+			ui.PrintWarning("%d required version(s) are not installed", len(missing))
+			ui.PrintHint("Run 'govm scan --install' to install them")
+			return nil
+		}
+		return installMissing(mgr, missing)
+	},
+}
+
+// scanProjectName returns a project directory relative to the scanned root
+func scanProjectName(root, dir string) string {
+	if rel, err := filepath.Rel(root, dir); err == nil {
+		return rel
+	}
+	return dir
+}
+
+// installMissing installs a version for every spec, skipping those an
+// earlier install in the batch already satisfies
+func installMissing(mgr *version.Manager, specs []string) error {
+	var failed int
+	for _, spec := range specs {
+		installed, err := mgr.ListInstalled()
+		if err != nil {
+			return err
+		}
+		if ver := version.SelectInstalled(spec, installed); ver != "" {
+			ui.PrintInfo("Go %s is satisfied by Go %s", spec, ver)
+			continue
+		}
+
+		if err := mgr.Install(config.ResolveVersion(spec), false, true); err != nil {
+			ui.PrintError("Go %s: %s", spec, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to install %d of %d version(s)", failed, len(specs))
+	}
+	ui.PrintSuccess("Installed every required version")
+	return nil
+}
+
+func init() {
+	scanCmd.Flags().BoolVar(&scanInstall, "install", false, "Install every missing version")
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/shell"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var (
+	shellUnset    bool
+	shellSubshell bool
+	shellEval     bool
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell [version|alias] [-- command [args...]]",
+	Short: "Use a Go version in the current shell only",
+	Long: `Activate a Go version for the current shell session only.
+
+The version is stored in GOVM_SHELL_VERSION and put in front of PATH.
+It overrides project detection and the global 'current' symlink, so other
+terminals, builds and editors are not affected.
+
+With shell integration ('govm init'), 'govm shell <version>' changes the
+current shell. Without it, or with --subshell, a new shell is started.
+Everything after '--' is run as a command with that version instead.
+
+Examples:
+  govm shell 1.22              Use Go 1.22 in this shell
+  govm shell --unset           Go back to the global version
+  govm shell 1.21 --subshell   Start a new shell using Go 1.21
+  govm shell 1.21 -- go test   Run a single command with Go 1.21
+  govm shell                   Show the session version`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// In eval mode stdout is evaluated by the shell wrapper, keep
+		// messages off it
+		if shellEval {
+			defer ui.SetOutput(ui.SetOutput(os.Stderr))
+		}
+
+		if shellUnset {
+			if !shellEval {
+				return fmt.Errorf("shell integration is not loaded, run 'eval \"$(govm init bash)\"' or start a new shell")
+			}
+			code, err := shell.SessionUnset()
+			if err != nil {
+				return err
+			}
+			fmt.Print(code)
+			return nil
+		}
+
+		if len(args) == 0 {
+			return showSessionVersion()
+		}
+
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		ver, err := mgr.EnsureInstalled(args[0])
+		if err != nil {
+			return err
+		}
+
+		if shellEval && !shellSubshell {
+			code, err := shell.SessionExports(ver)
+			if err != nil {
+				return err
+			}
+			fmt.Print(code)
+			ui.PrintSuccess("Using Go %s in this shell", ver)
+			return nil
+		}
+
+		env, err := sessionEnviron(ver)
+		if err != nil {
+			return err
+		}
+
+		// Run a single command
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 && dash < len(args) {
+			command := args[dash:]
+			return executeCommand(command[0], command[1:], env)
+		}
+
+		// Start a subshell
+		userShell := os.Getenv("SHELL")
+		if userShell == "" {
+			userShell = "/bin/sh"
+		}
+		ui.PrintInfo("Starting %s with Go %s (exit to return)", userShell, ver)
+		return executeCommand(userShell, nil, env)
+	},
+}
+
+// sessionEnviron returns the current environment with a session version applied
+func sessionEnviron(ver string) ([]string, error) {
+	vars, err := shell.SessionEnv(ver)
+	if err != nil {
+		return nil, err
+	}
+	return vars.Apply(os.Environ()), nil
+}
+
+func showSessionVersion() error {
+	ver, source := version.SessionVersion()
+	if ver == "" {
+		ui.PrintInfo("No session version is set, using the global version")
+		ui.PrintHint("Run 'govm shell <version>' to use a version in this shell only")
+		return nil
+	}
+
+	ui.PrintKeyValue("Session", ui.GreenBold.Sprint(ver))
+	if source == config.EnvAutoVersion {
+		ui.PrintKeyValue("Set by", "auto-switch (switch_scope = session)")
+	}
+	return nil
+}
+
+func init() {
+	shellCmd.Flags().BoolVarP(&shellUnset, "unset", "u", false, "Remove the session version")
+	shellCmd.Flags().BoolVarP(&shellSubshell, "subshell", "s", false, "Start a new shell with the version")
+	shellCmd.Flags().BoolVar(&shellEval, "eval", false, "Print shell code for the shell integration")
+	shellCmd.Flags().MarkHidden("eval")
+}
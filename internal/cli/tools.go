@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Manage the tools installed into every Go version",
+	Long: `Manage the default tool set from the [tools] section of config.toml.
+
+Each entry maps a package path to a version or constraint; every newly
+installed Go version runs 'go install' for them:
+
+  [tools]
+  "golang.org/x/tools/gopls" = "latest"
+  "github.com/go-delve/delve/cmd/dlv" = "^1.23"
+  "honnef.co/go/tools/cmd/staticcheck" = "2024.1.1"
+
+Examples:
+  govm tools                   Show the tools of the current version
+  govm tools list 1.22         Show the tools of Go 1.22
+  govm tools sync              Install the manifest for the current version
+  govm tools sync 1.23         Install the manifest for Go 1.23`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listTools("")
+	},
+}
+
+var toolsListCmd = &cobra.Command{
+	Use:   "list [version]",
+	Short: "Show manifest and installed tools of a Go version",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+		return listTools(spec)
+	},
+}
+
+var toolsSyncCmd = &cobra.Command{
+	Use:   "sync [version]",
+	Short: "Install the [tools] manifest with a Go version",
+	Long: `Run 'go install' for every tool in the [tools] manifest with the given
+Go version (default: current). Every tool is attempted; the command fails
+if any of them could not be installed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec := ""
+		if len(args) > 0 {
+			spec = args[0]
+		}
+
+		mgr, ver, err := toolsVersion(spec)
+		if err != nil {
+			return err
+		}
+
+		tools := version.ManifestTools()
+		if len(tools) == 0 {
+			ui.PrintInfo("No tools in the [tools] manifest")
+			ui.PrintHint("Add them to the [tools] section of %s", configFilePath())
+			return nil
+		}
+
+		ui.PrintInfo("Installing %d tool(s) for Go %s...", len(tools), ver)
+		if failed := version.FailedTools(mgr.InstallTools(ver, tools)); len(failed) > 0 {
+			return fmt.Errorf("%d of %d tool(s) failed to install", len(failed), len(tools))
+		}
+		return nil
+	},
+}
+
+// listTools shows the manifest next to the tools built by a Go version
+func listTools(spec string) error {
+	mgr, ver, err := toolsVersion(spec)
+	if err != nil {
+		return err
+	}
+
+	installed, err := mgr.InstalledTools(ver)
+	if err != nil {
+		return err
+	}
+	manifest := version.ManifestTools()
+
+	if len(installed) == 0 && len(manifest) == 0 {
+		ui.PrintInfo("No tools installed for Go %s", ver)
+		ui.PrintHint("Add tools to the [tools] section of %s", configFilePath())
+		return nil
+	}
+
+	installedVersions := make(map[string]string)
+	for _, t := range installed {
+		installedVersions[t.Path] = t.Version
+	}
+
+	dir, _ := mgr.ToolsDir(ver)
+	ui.PrintHeader(fmt.Sprintf("Tools for Go %s", ver))
+
+	table := ui.NewTable("Tool", "Manifest", "Installed")
+	listed := make(map[string]bool)
+	for _, t := range manifest {
+		listed[t.Path] = true
+		inst := ui.Warning.Sprint("missing")
+		if v, ok := installedVersions[t.Path]; ok {
+			inst = ui.Green.Sprint(v)
+		}
+		table.AddRow(t.Path, t.Version, inst)
+	}
+	for _, t := range installed {
+		if !listed[t.Path] {
+			table.AddRow(t.Path, "-", t.Version)
+		}
+	}
+	table.Render()
+
+	ui.Println()
+	ui.PrintKeyValue("Directory", ui.Path.Sprint(dir))
+	return nil
+}
+
+// toolsVersion resolves the Go version a tools command applies to
+// (default: current)
+func toolsVersion(spec string) (*version.Manager, string, error) {
+	mgr, err := version.NewManager()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if spec == "" {
+		current, err := mgr.Current()
+		if err != nil || current == "" {
+			return nil, "", fmt.Errorf("no Go version is active (run 'govm use <version>')")
+		}
+		return mgr, current, nil
+	}
+
+	spec = config.ResolveVersion(spec)
+	matched, err := mgr.ResolveInstalled(spec)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(matched) == 0 {
+		return nil, "", fmt.Errorf("version %s is not installed. Run 'govm install %s' first", spec, spec)
+	}
+	return mgr, matched[0], nil
+}
+
+// configFilePath returns the path of config.toml for hints
+func configFilePath() string {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return "config.toml"
+	}
+	return paths.Config
+}
+
+func init() {
+	toolsCmd.AddCommand(toolsListCmd)
+	toolsCmd.AddCommand(toolsSyncCmd)
+}
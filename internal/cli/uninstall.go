@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/wenzzy/govm/internal/ui"
 	"github.com/wenzzy/govm/internal/version"
@@ -9,37 +11,141 @@ import (
 var forceUninstall bool
 
 var uninstallCmd = &cobra.Command{
-	Use:     "uninstall <version>",
+	Use:     "uninstall <version|alias|constraint>...",
 	Aliases: []string{"rm", "remove", "delete"},
-	Short:   "Uninstall a Go version",
-	Long: `Uninstall a specific Go version.
+	Short:   "Uninstall Go versions",
+	Long: `Uninstall one or more installed Go versions.
+
+Each argument can be an exact version, an alias, a partial version
+(matching every installed patch release) or a constraint expression.
+The resolved versions are listed for confirmation before removal.
 
 Examples:
   govm uninstall 1.21.0       Uninstall Go 1.21.0
-  govm rm 1.20.0              Short form
-  g rm 1.19.0 -f              Force uninstall without confirmation`,
-	Args: cobra.ExactArgs(1),
+  govm rm 1.20                Uninstall every installed 1.20.x
+  govm rm 1.19.0 1.20.3       Uninstall several versions
+  govm rm '<1.21'             Uninstall everything older than 1.21
+  govm rm '1.19.*'            Uninstall every 1.19 patch release
+  g rm dev -f                 Uninstall the version behind alias 'dev' without confirmation`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		mgr, err := version.NewManager()
 		if err != nil {
 			return err
 		}
 
-		ver := args[0]
+		versions, err := resolveUninstallTargets(mgr, args)
+		if err != nil {
+			return err
+		}
 
-		// Check if it's the current version
 		current, _ := mgr.Current()
-		if current == ver && !forceUninstall {
-			if !ui.Confirm(ui.Warning.Sprintf("Version %s is currently in use. Uninstall anyway?", ver)) {
+
+		if !forceUninstall {
+			ui.PrintHeader("Versions to uninstall")
+			for _, ver := range versions {
+				if ver == current {
+					fmt.Printf("  %s %s\n", ui.Warning.Sprint(ver), ui.Dim.Sprint("(current, will switch to another version)"))
+				} else {
+					fmt.Printf("  %s\n", ver)
+				}
+			}
+			ui.Println()
+
+			if !ui.Confirm(fmt.Sprintf("Uninstall %d version(s)?", len(versions))) {
 				ui.PrintInfo("Aborted")
 				return nil
 			}
 		}
 
-		return mgr.Uninstall(ver)
+		// Neither current nor references move to a version of the batch
+		batch := make(map[string]bool)
+		for _, ver := range versions {
+			batch[ver] = true
+		}
+
+		var failed int
+		for _, ver := range versions {
+			if err := mgr.Uninstall(ver, batch); err != nil {
+				ui.PrintError("Go %s: %s", ver, err)
+				failed++
+				continue
+			}
+			if forceUninstall {
+				for _, ref := range mgr.FindReferences(ver) {
+					ui.PrintWarning("Go %s is still referenced by %s", ver, ref)
+				}
+				continue
+			}
+			if err := fixReferences(mgr, ver, batch); err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to uninstall %d of %d version(s)", failed, len(versions))
+		}
+		return nil
 	},
 }
 
+// resolveUninstallTargets expands every argument to installed versions,
+// keeping the first occurrence of each version
+func resolveUninstallTargets(mgr *version.Manager, args []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var versions []string
+
+	for _, arg := range args {
+		matched, err := mgr.ResolveInstalled(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no installed version matches %s", arg)
+		}
+		for _, ver := range matched {
+			if !seen[ver] {
+				seen[ver] = true
+				versions = append(versions, ver)
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// fixReferences offers to re-point or remove aliases and default_version
+// that still point at a removed version, never re-pointing them to a
+// version of the batch
+func fixReferences(mgr *version.Manager, ver string, batch map[string]bool) error {
+	refs := mgr.FindReferences(ver)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	if replacement := mgr.Replacement(ver, batch); replacement != "" {
+		if ui.Confirm(fmt.Sprintf("Re-point %d reference(s) to Go %s?", len(refs), replacement)) {
+			if err := mgr.RepointReferences(refs, replacement); err != nil {
+				return err
+			}
+			for _, ref := range refs {
+				ui.PrintSuccess("Re-pointed %s -> %s", ref, replacement)
+			}
+			return nil
+		}
+	}
+
+	if ui.Confirm(fmt.Sprintf("Remove %d reference(s) to Go %s?", len(refs), ver)) {
+		if err := mgr.RemoveReferences(refs); err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			ui.PrintSuccess("Removed %s", ref)
+		}
+	}
+	return nil
+}
+
 func init() {
 	uninstallCmd.Flags().BoolVarP(&forceUninstall, "force", "f", false, "Force uninstall without confirmation")
 }
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// testManager returns a manager for a temporary GOVM_ROOT with stub
+// installs of versions and the given config.toml
+func testManager(t *testing.T, configTOML string, versions ...string) *version.Manager {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("GOVM_ROOT", root)
+	for _, v := range versions {
+		bin := filepath.Join(root, "versions", v, "go", "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "config.toml"), []byte(configTOML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.Reload() })
+
+	mgr, err := version.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mgr
+}
+
+func TestResolveUninstallTargets(t *testing.T) {
+	mgr := testManager(t, "[aliases]\ndev = '1.21.2'\n",
+		"1.19.13", "1.20", "1.20.2", "1.20.14", "1.21.2", "1.22.0")
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"exact", []string{"1.20.2"}, []string{"1.20.2"}},
+		{"partial", []string{"1.20"}, []string{"1.20.14", "1.20.2", "1.20"}},
+		{"constraint", []string{"<1.20.3"}, []string{"1.20.2", "1.20", "1.19.13"}},
+		{"wildcard", []string{"1.19.*"}, []string{"1.19.13"}},
+		{"alias", []string{"dev"}, []string{"1.21.2"}},
+		{"several", []string{"1.22.0", "1.19.13"}, []string{"1.22.0", "1.19.13"}},
+		{"duplicates kept once", []string{"1.20.2", "1.20", "~1.20"}, []string{"1.20.2", "1.20.14", "1.20"}},
+	}
+	for _, tt := range tests {
+		got, err := resolveUninstallTargets(mgr, tt.args)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: resolveUninstallTargets(%q) = %v, want %v", tt.name, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestResolveUninstallTargetsErrors(t *testing.T) {
+	mgr := testManager(t, "", "1.21.2")
+
+	for _, args := range [][]string{
+		{"1.22"},           // Nothing installed matches
+		{"1.21.2", "1.23"}, // One argument without a match fails the batch
+		{"<abc"},           // Invalid constraint
+	} {
+		if got, err := resolveUninstallTargets(mgr, args); err == nil {
+			t.Errorf("resolveUninstallTargets(%q) = %v, want an error", args, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+var (
+	updateRepoint bool
+	updateSwitch  bool
+	updatePrune   bool
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [minor...]",
+	Short: "Install the latest patch release of installed minor lines",
+	Long: `Install the newest patch release for every outdated installed minor line,
+or only for the given lines. See 'govm outdated' for what would change.
+
+The older patches of a line are superseded by the new one. Optionally:
+  --repoint   aliases and default_version that point at a superseded patch
+              are re-pointed to the new one
+  --switch    if current is a superseded patch, switch to the new one
+  --prune     superseded patches are uninstalled afterwards, except one
+              that is still current (without --switch) or referenced by
+              an alias or default_version (without --repoint)
+
+To upgrade govm itself, use 'govm upgrade'.
+
+Examples:
+  govm update                         Update every outdated line
+  govm update 1.21 1.22               Update the 1.21 and 1.22 lines
+  govm update --repoint --switch      Update and move references and current
+  govm update --repoint --switch --prune  Also remove the old patches`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := version.NewManager()
+		if err != nil {
+			return err
+		}
+
+		spinner := ui.NewSpinner("Checking for patch releases...")
+		spinner.Start()
+		outdated, err := mgr.ListOutdated()
+		spinner.Stop()
+		if err != nil {
+			return err
+		}
+
+		outdated = outdatedLines(outdated, args)
+		if len(outdated) == 0 {
+			ui.PrintSuccess("Nothing to update")
+			return nil
+		}
+
+		var failed int
+		for _, o := range outdated {
+			if err := updateLine(mgr, o); err != nil {
+				ui.PrintError("Go %s: %s", o.Minor, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to update %d of %d line(s)", failed, len(outdated))
+		}
+		return nil
+	},
+}
+
+// updateLine installs the latest patch of a minor line and applies the
+// optional follow-up actions to its superseded patches
+func updateLine(mgr *version.Manager, o version.Outdated) error {
+	note := ""
+	if len(o.Security) > 0 {
+		note = ui.Warning.Sprintf(" (security: %s)", strings.Join(o.Security, ", "))
+	}
+	ui.PrintInfo("Updating Go %s: %s -> %s%s", o.Minor, o.Installed, o.Latest, note)
+
+	if err := mgr.Install(o.Latest, false, true); err != nil {
+		return err
+	}
+
+	current, _ := mgr.Current()
+	var skipped []string
+	inUse := make(map[string]bool) // Superseded patches --prune must keep
+
+	for _, old := range o.Superseded {
+		if refs := mgr.FindReferences(old); len(refs) > 0 {
+			if updateRepoint {
+				if err := mgr.RepointReferences(refs, o.Latest); err != nil {
+					return err
+				}
+				for _, ref := range refs {
+					ui.PrintSuccess("Re-pointed %s -> %s", ref, o.Latest)
+				}
+			} else {
+				for _, ref := range refs {
+					skipped = append(skipped, fmt.Sprintf("%s still points to Go %s, re-point it with --repoint", ref, old))
+				}
+				inUse[old] = true
+			}
+		}
+
+		if old == current {
+			if updateSwitch {
+				if err := mgr.Use(o.Latest); err != nil {
+					return err
+				}
+			} else {
+				skipped = append(skipped, fmt.Sprintf("Current is still Go %s, switch with --switch", old))
+				inUse[old] = true
+			}
+		}
+	}
+
+	if updatePrune {
+		batch := make(map[string]bool)
+		for _, old := range o.Superseded {
+			if !inUse[old] {
+				batch[old] = true
+			}
+		}
+		for _, old := range o.Superseded {
+			if inUse[old] {
+				ui.PrintInfo("Keeping Go %s, it is still in use", old)
+				continue
+			}
+			if err := mgr.Uninstall(old, batch); err != nil {
+				ui.PrintWarning("Failed to remove Go %s: %s", old, err)
+			}
+		}
+	}
+
+	for _, s := range skipped {
+		ui.PrintHint("%s", s)
+	}
+	return nil
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateRepoint, "repoint", false, "Re-point aliases and default_version from superseded patches")
+	updateCmd.Flags().BoolVar(&updateSwitch, "switch", false, "Switch current from a superseded patch to the new one")
+	updateCmd.Flags().BoolVar(&updatePrune, "prune", false, "Uninstall superseded patches")
+}
@@ -10,10 +10,14 @@ var (
 )
 
 var useCmd = &cobra.Command{
-	Use:     "use <version|alias>",
+	Use:     "use <version|alias|constraint>",
 	Aliases: []string{"switch", "select"},
 	Short:   "Switch to a Go version",
-	Long: `Switch to a specific Go version or alias.
+	Long: `Switch to a specific Go version, alias or constraint expression.
+
+Partial versions and constraints (~1.21, ^1.22, '>=1.21 <1.23', 1.22.x) are
+resolved by version order, against installed versions first and then
+against the go.dev release index.
 
 If the version is not installed and auto-install is enabled,
 it will be downloaded and installed automatically.
@@ -22,7 +26,9 @@ Examples:
   govm use 1.22.0             Switch to Go 1.22.0
   govm use 1.22 --default     Switch and set as default
   govm use stable             Switch to the stable alias
-  govm use .                  Use version from go.mod/go.work
+  govm use '~1.21'            Switch to the newest 1.21.x
+  govm use '>=1.21 <1.23'     Switch to the newest version in a range
+  govm use .                  Use version from .go-version, .tool-versions, go.work or go.mod
   g use 1.21.0                Short form`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
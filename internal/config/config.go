@@ -2,17 +2,102 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
+// Switch scopes for the auto-switch shell hook
+const (
+	ScopeGlobal  = "global"  // Rewrite the ~/.govm/current symlink
+	ScopeSession = "session" // Change PATH/GOROOT of the current shell only
+)
+
+// Actions the auto-switch hook takes when leaving a project directory
+const (
+	LeaveDefault  = "default"  // Switch to default_version
+	LeavePrevious = "previous" // Switch back to the version active before entering the project
+	LeaveKeep     = "keep"     // Keep the project version
+)
+
+// Stop markers for the inherit_version parent search
+const (
+	StopGit  = "git"  // The repository root: the first directory with a .git entry, which is still searched
+	StopHome = "home" // $HOME, which is only searched when the search starts there
+)
+
+// InheritStops lists the valid inherit_stop values
+var InheritStops = []string{StopGit, StopHome}
+
+// Per-version isolation settings for the isolate option. Each isolated
+// setting points at a directory of its own under ~/.govm/envs/<version>.
+const (
+	IsolateGobin    = "gobin"    // GOBIN: tools built by 'go install'
+	IsolateGopath   = "gopath"   // GOPATH
+	IsolateGoenv    = "goenv"    // GOENV: settings written by 'go env -w'
+	IsolateModcache = "modcache" // GOMODCACHE, shared by default even with an isolated GOPATH
+)
+
+// IsolateSettings lists the valid isolate values
+var IsolateSettings = []string{IsolateGobin, IsolateGopath, IsolateGoenv, IsolateModcache}
+
 // Config represents the govm configuration
 type Config struct {
-	DefaultVersion string            `toml:"default_version"`
-	AutoInstall    bool              `toml:"auto_install"`
-	InheritVersion bool              `toml:"inherit_version"` // Search parent dirs for go.mod/go.work
-	Aliases        map[string]string `toml:"aliases"`
+	DefaultVersion string                   `toml:"default_version"`
+	AutoInstall    bool                     `toml:"auto_install"`
+	InheritVersion bool                     `toml:"inherit_version"`               // Search parent dirs for go.mod/go.work
+	InheritStop    []string                 `toml:"inherit_stop"`                  // Stop* markers ending the parent search
+	InheritDepth   int                      `toml:"inherit_max_depth"`             // Parent directories searched at most, 0 for no limit
+	Ceilings       []string                 `toml:"ceiling_directories,omitempty"` // Directories the parent search never enters
+	SwitchScope    string                   `toml:"switch_scope"`                  // ScopeGlobal or ScopeSession
+	OnLeave        string                   `toml:"on_leave"`                      // LeaveDefault, LeavePrevious or LeaveKeep
+	Isolate        []string                 `toml:"isolate,omitempty"`             // Isolate* settings kept per version
+	Aliases        map[string]string        `toml:"aliases"`
+	Versions       map[string]VersionConfig `toml:"versions,omitempty"` // Keyed by version, minor or constraint
+	Tools          map[string]string        `toml:"tools,omitempty"`    // Package path -> version installed into every new toolchain
+}
+
+// VersionConfig holds settings for the Go versions matching a spec
+type VersionConfig struct {
+	Env map[string]string `toml:"env,omitempty"` // Variables set while the version is active
+}
+
+// Isolates reports whether setting (one of the Isolate* constants) is kept per version
+func (c *Config) Isolates(setting string) bool {
+	for _, s := range c.Isolate {
+		if s == setting {
+			return true
+		}
+	}
+	return false
+}
+
+// Stops reports whether the parent search stops at marker (one of the Stop* constants)
+func (c *Config) Stops(marker string) bool {
+	for _, s := range c.InheritStop {
+		if s == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// CeilingDirs returns ceiling_directories as clean absolute paths, with a
+// leading ~ expanded to the home directory
+func (c *Config) CeilingDirs() []string {
+	home, _ := os.UserHomeDir()
+	var dirs []string
+	for _, dir := range c.Ceilings {
+		if rest, ok := strings.CutPrefix(dir, "~"); ok && home != "" && (rest == "" || rest[0] == '/') {
+			dir = home + rest
+		}
+		if dir, err := filepath.Abs(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
 
 var (
@@ -27,6 +112,9 @@ func DefaultConfig() *Config {
 		DefaultVersion: "",
 		AutoInstall:    true,
 		InheritVersion: false, // Only check current directory by default
+		InheritStop:    []string{StopGit, StopHome},
+		SwitchScope:    ScopeGlobal,
+		OnLeave:        LeaveDefault,
 		Aliases: map[string]string{
 			"stable": "",
 			"latest": "",
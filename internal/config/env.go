@@ -0,0 +1,11 @@
+package config
+
+// Environment variables that carry per-shell state between govm and the shell hooks
+const (
+	// EnvShellVersion holds the version activated with 'govm shell'.
+	// It overrides project detection and the global current symlink.
+	EnvShellVersion = "GOVM_SHELL_VERSION"
+	// EnvAutoVersion holds the version the auto-switch hook activated
+	// when switch_scope is "session"
+	EnvAutoVersion = "GOVM_AUTO_VERSION"
+)
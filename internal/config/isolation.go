@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsolatedVar is an environment variable set by the isolate setting
+type IsolatedVar struct {
+	Key   string
+	Value string
+}
+
+// Isolation returns GOMODCACHE, GOPATH, GOBIN and GOENV for version according
+// to the isolate setting, and the directory that holds the version's
+// installed tools ("" when tools are not isolated). With an isolated GOPATH
+// the module cache stays shared unless modcache is isolated as well.
+func (p *Paths) Isolation(cfg *Config, version string) ([]IsolatedVar, string) {
+	if len(cfg.Isolate) == 0 || version == "" {
+		return nil, ""
+	}
+
+	dir := p.IsolationPath(version)
+	var vars []IsolatedVar
+	toolsBin := ""
+
+	if cfg.Isolates(IsolateGopath) {
+		// Resolve the shared cache before GOPATH is replaced
+		if !cfg.Isolates(IsolateModcache) {
+			vars = append(vars, IsolatedVar{"GOMODCACHE", p.SharedModCache()})
+		}
+		vars = append(vars, IsolatedVar{"GOPATH", filepath.Join(dir, "gopath")})
+		toolsBin = filepath.Join(dir, "gopath", "bin")
+	}
+	if cfg.Isolates(IsolateGobin) {
+		vars = append(vars, IsolatedVar{"GOBIN", filepath.Join(dir, "bin")})
+		toolsBin = filepath.Join(dir, "bin")
+	}
+	if cfg.Isolates(IsolateGoenv) {
+		vars = append(vars, IsolatedVar{"GOENV", filepath.Join(dir, "go.env")})
+	}
+
+	return vars, toolsBin
+}
+
+// SharedModCache returns the module cache shared by all versions: GOMODCACHE
+// or the first GOPATH entry's pkg/mod, ignoring values that point into an
+// isolated per-version directory
+func (p *Paths) SharedModCache() string {
+	if cache := os.Getenv("GOMODCACHE"); cache != "" && !p.IsIsolated(cache) {
+		return cache
+	}
+	return filepath.Join(p.SharedGopath(), "pkg", "mod")
+}
+
+// SharedGopath returns the first GOPATH entry, or ~/go, ignoring values
+// that point into an isolated per-version directory
+func (p *Paths) SharedGopath() string {
+	if list := filepath.SplitList(os.Getenv("GOPATH")); len(list) > 0 && list[0] != "" && !p.IsIsolated(list[0]) {
+		return list[0]
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "go")
+}
+
+// IsIsolated reports whether path is inside a per-version directory
+func (p *Paths) IsIsolated(path string) bool {
+	return strings.HasPrefix(path, p.Envs+string(os.PathSeparator))
+}
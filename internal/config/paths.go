@@ -26,6 +26,7 @@ type Paths struct {
 	Cache      string // ~/.govm/cache
 	Config     string // ~/.govm/config.toml
 	Bin        string // ~/.govm/bin
+	Envs       string // ~/.govm/envs (per-version GOBIN, GOPATH, GOENV)
 }
 
 // GetPaths returns the paths for govm
@@ -49,6 +50,7 @@ func GetPaths() (*Paths, error) {
 		Cache:    filepath.Join(root, "cache"),
 		Config:   filepath.Join(root, "config.toml"),
 		Bin:      filepath.Join(root, "bin"),
+		Envs:     filepath.Join(root, "envs"),
 	}, nil
 }
 
@@ -73,6 +75,12 @@ func (p *Paths) VersionBinPath(version string) string {
 	return filepath.Join(p.Versions, version, "go", "bin")
 }
 
+// IsolationPath returns the directory holding a version's isolated GOBIN,
+// GOPATH and GOENV
+func (p *Paths) IsolationPath(version string) string {
+	return filepath.Join(p.Envs, version)
+}
+
 // CachePath returns the path for a cached archive
 func (p *Paths) CachePath(filename string) string {
 	return filepath.Join(p.Cache, filename)
@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// Finding levels, named as in SARIF
+const (
+	LevelError   = "error"   // The declared version is older than the requirement
+	LevelWarning = "warning" // The declared version is on another minor line
+)
+
+// Rule IDs of the findings
+const (
+	RuleTooOld   = "go-version-too-old"
+	RuleMismatch = "go-version-mismatch"
+)
+
+// Finding is a declaration that disagrees with the reference
+type Finding struct {
+	Declaration
+	Rule    string `json:"rule"`  // RuleTooOld or RuleMismatch
+	Level   string `json:"level"` // LevelError or LevelWarning
+	Message string `json:"message"`
+}
+
+// Report is the result of linting a directory
+type Report struct {
+	Root         string        `json:"root"`
+	Reference    Declaration   `json:"reference"` // What every other declaration is compared with
+	Declarations []Declaration `json:"declarations"`
+	Findings     []Finding     `json:"findings"`
+}
+
+// Check collects the Go versions declared in root and compares them with
+// the module's requirement: go.work, else go.mod, else .go-version when
+// there is no module file. Checked are go.mod/go.work and .go-version in
+// root, the go-version inputs and golang images in .github/workflows,
+// Dockerfiles anywhere in the tree that no .gitignore ignores, and the
+// golang images of .gitlab-ci.yml.
+func Check(root string) (*Report, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &collector{root: root, seen: make(map[string]bool)}
+	hasModule := c.goFile(KindGoWork, filepath.Join(root, "go.work"))
+	hasModule = c.goFile(KindGoMod, filepath.Join(root, "go.mod")) || hasModule
+	hasPin := c.goVersionPin(filepath.Join(root, ".go-version"))
+	if !hasModule && !hasPin {
+		return nil, fmt.Errorf("no go.work, go.mod or .go-version with a Go version in %s", root)
+	}
+
+	for _, path := range workflowFiles(root) {
+		c.workflow(path)
+	}
+	files, err := dockerfiles(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		c.dockerfile(path)
+	}
+	c.gitlabCI(filepath.Join(root, ".gitlab-ci.yml"))
+
+	// Keep files in the order they were read, and each file's lines in order
+	fileOrder := make(map[string]int)
+	for _, d := range c.decls {
+		if _, ok := fileOrder[d.File]; !ok {
+			fileOrder[d.File] = len(fileOrder)
+		}
+	}
+	sort.SliceStable(c.decls, func(i, j int) bool {
+		a, b := c.decls[i], c.decls[j]
+		if a.File != b.File {
+			return fileOrder[a.File] < fileOrder[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	report := &Report{Root: root, Reference: c.decls[0], Declarations: c.decls}
+	for _, d := range c.decls[1:] {
+		if f, ok := compare(d, report.Reference); ok {
+			report.Findings = append(report.Findings, f)
+		}
+	}
+	return report, nil
+}
+
+// compare checks a declaration against the reference. A version on the
+// reference's minor line agrees unless both name a patch or pre-release and
+// it is older.
+func compare(d, ref Declaration) (Finding, bool) {
+	f := Finding{Declaration: d}
+
+	// The go.mod of a workspace module is a minimum: it may be older than
+	// go.work, but the go command refuses a go.work older than a module
+	if d.Kind == KindGoMod && ref.Kind == KindGoWork {
+		if version.CompareVersions(d.Version, ref.Version) <= 0 {
+			return f, false
+		}
+		f.Rule, f.Level = RuleTooOld, LevelError
+		f.Message = fmt.Sprintf("go.mod requires Go %s, newer than Go %s declared by %s", d.Version, ref.Version, ref.Location())
+		return f, true
+	}
+
+	dLine, refLine := version.MinorLine(d.Version), version.MinorLine(ref.Version)
+	precise := d.Version != dLine && ref.Version != refLine
+
+	switch c := version.CompareVersions(dLine, refLine); {
+	case c < 0 || (c == 0 && precise && version.CompareVersions(d.Version, ref.Version) < 0):
+		f.Rule, f.Level = RuleTooOld, LevelError
+		f.Message = fmt.Sprintf("%s uses Go %s, older than Go %s required by %s", d.Kind, d.Version, ref.Version, ref.Location())
+	case c > 0:
+		f.Rule, f.Level = RuleMismatch, LevelWarning
+		f.Message = fmt.Sprintf("%s uses Go %s, but %s requires Go %s", d.Kind, d.Version, ref.Location(), ref.Version)
+	default:
+		return f, false
+	}
+	return f, true
+}
@@ -0,0 +1,243 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTree creates files under a temporary directory and returns it
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+const workflow = `name: ci
+env:
+  GO_VERSION: "1.22.1" # pinned
+jobs:
+  test:
+    strategy:
+      matrix:
+        go: [1.21, 1.22]
+    steps:
+      - uses: actions/setup-go@v5
+        with:
+          go-version: ${{ matrix.go }}
+      - uses: actions/setup-go@v5
+        with:
+          go-version: ${{ env.GO_VERSION }}
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+  lint:
+    container: golang:1.23-alpine
+`
+
+const dockerfile = `ARG GO_VERSION=1.22.3
+FROM --platform=$BUILDPLATFORM golang:${GO_VERSION} AS build
+FROM golang:1.22-bookworm
+FROM alpine:3.20
+`
+
+const gitlabCI = `variables:
+  GO_IMAGE_TAG: "1.20"
+test:
+  image: golang:$GO_IMAGE_TAG
+`
+
+func TestCheck(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"go.mod":                   "module example.com/m\n\ngo 1.22\n\ntoolchain go1.22.3\n",
+		".go-version":              "1.22.3\n",
+		".github/workflows/ci.yml": workflow,
+		"Dockerfile":               dockerfile,
+		"build/Dockerfile.ci":      "FROM golang:1.22.0\n",
+		"ignored/Dockerfile":       "FROM golang:1.19\n",
+		".gitignore":               "ignored/\n",
+		".gitlab-ci.yml":           gitlabCI,
+	})
+
+	report, err := Check(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Declaration{
+		{Kind: KindGoMod, File: "go.mod", Line: 5, Value: "1.22.3", Version: "1.22.3"},
+		{Kind: KindGoVersion, File: ".go-version", Line: 1, Value: "1.22.3", Version: "1.22.3"},
+		{Kind: KindSetupGo, File: ".github/workflows/ci.yml", Line: 3, Value: "1.22.1", Version: "1.22.1"},
+		{Kind: KindSetupGo, File: ".github/workflows/ci.yml", Line: 8, Value: "1.21", Version: "1.21"},
+		{Kind: KindSetupGo, File: ".github/workflows/ci.yml", Line: 8, Value: "1.22", Version: "1.22"},
+		{Kind: KindImage, File: ".github/workflows/ci.yml", Line: 20, Value: "1.23-alpine", Version: "1.23"},
+		{Kind: KindDockerfile, File: "Dockerfile", Line: 1, Value: "1.22.3", Version: "1.22.3"},
+		{Kind: KindDockerfile, File: "Dockerfile", Line: 3, Value: "1.22-bookworm", Version: "1.22"},
+		{Kind: KindDockerfile, File: "build/Dockerfile.ci", Line: 1, Value: "1.22.0", Version: "1.22.0"},
+		{Kind: KindImage, File: ".gitlab-ci.yml", Line: 2, Value: "1.20", Version: "1.20"},
+	}
+	if len(report.Declarations) != len(want) {
+		t.Fatalf("got %d declarations, want %d: %+v", len(report.Declarations), len(want), report.Declarations)
+	}
+	for i, d := range report.Declarations {
+		if d != want[i] {
+			t.Errorf("declaration %d = %+v, want %+v", i, d, want[i])
+		}
+	}
+	if report.Reference != want[0] {
+		t.Errorf("reference = %+v, want go.mod", report.Reference)
+	}
+
+	findings := map[string]string{} // location:version -> rule
+	for _, f := range report.Findings {
+		findings[f.Location()+":"+f.Version] = f.Rule
+	}
+	wantFindings := map[string]string{
+		".github/workflows/ci.yml:3:1.22.1": RuleTooOld,
+		".github/workflows/ci.yml:8:1.21":   RuleTooOld,
+		".github/workflows/ci.yml:20:1.23":  RuleMismatch,
+		"build/Dockerfile.ci:1:1.22.0":      RuleTooOld,
+		".gitlab-ci.yml:2:1.20":             RuleTooOld,
+	}
+	if len(findings) != len(wantFindings) {
+		t.Errorf("findings = %v, want %v", findings, wantFindings)
+	}
+	for key, rule := range wantFindings {
+		if findings[key] != rule {
+			t.Errorf("finding %s = %q, want %q", key, findings[key], rule)
+		}
+	}
+}
+
+func TestCheckWorkspace(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"go.work": "go 1.22.1\n\nuse .\n",
+		"go.mod":  "module example.com/m\n\ngo 1.23\n",
+	})
+	report, err := Check(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Reference.Kind != KindGoWork {
+		t.Errorf("reference = %+v, want go.work", report.Reference)
+	}
+	// A module newer than its workspace breaks the build
+	if len(report.Findings) != 1 || report.Findings[0].Rule != RuleTooOld || report.Findings[0].Kind != KindGoMod {
+		t.Errorf("findings = %+v, want go.mod newer than go.work", report.Findings)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ref := Declaration{Kind: KindGoMod, File: "go.mod", Line: 3, Version: "1.22.3"}
+	tests := []struct {
+		version string
+		rule    string // "" when it agrees
+	}{
+		{"1.22.3", ""},
+		{"1.22.5", ""},
+		{"1.22", ""},
+		{"1.22.1", RuleTooOld},
+		{"1.22rc1", RuleTooOld},
+		{"1.21", RuleTooOld},
+		{"1.23", RuleMismatch},
+	}
+	for _, tt := range tests {
+		got := ""
+		if f, ok := compare(Declaration{Kind: KindDockerfile, Version: tt.version}, ref); ok {
+			got = f.Rule
+		}
+		if got != tt.rule {
+			t.Errorf("compare(%s) = %q, want %q", tt.version, got, tt.rule)
+		}
+	}
+}
+
+func TestCheckWithoutRequirement(t *testing.T) {
+	root := writeTree(t, map[string]string{"Dockerfile": "FROM golang:1.22\n"})
+	if _, err := Check(root); err == nil {
+		t.Error("Check succeeded without go.mod, go.work or .go-version")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	report := &Report{Findings: []Finding{{
+		Declaration: Declaration{Kind: KindDockerfile, File: "build/Dockerfile", Line: 4, Version: "1.21"},
+		Rule:        RuleTooOld,
+		Level:       LevelError,
+		Message:     "Dockerfile uses Go 1.21, older than Go 1.22 required by go.mod:3",
+	}}}
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string                `json:"ruleId"`
+				Level     string                `json:"level"`
+				Message   struct{ Text string } `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string } `json:"artifactLocation"`
+						Region           struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version %q with %d runs, want 2.1.0 with one run", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "govm" || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("driver = %+v, want govm with two rules", run.Tool.Driver)
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(run.Results))
+	}
+	r := run.Results[0]
+	loc := r.Locations[0].PhysicalLocation
+	if r.RuleID != RuleTooOld || r.Level != LevelError || r.Message.Text != report.Findings[0].Message ||
+		loc.ArtifactLocation.URI != "build/Dockerfile" || loc.Region.StartLine != 4 {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestWriteSARIFNoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, &Report{}); err != nil {
+		t.Fatal(err)
+	}
+	// Consumers expect an empty list rather than null
+	if !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Errorf("SARIF without findings has no empty results list:\n%s", buf.String())
+	}
+}
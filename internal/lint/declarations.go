@@ -0,0 +1,292 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wenzzy/govm/internal/scan"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// Kinds of places a Go version is declared
+const (
+	KindGoWork     = "go.work"
+	KindGoMod      = "go.mod"
+	KindGoVersion  = ".go-version"
+	KindSetupGo    = "setup-go"     // go-version input of actions/setup-go
+	KindDockerfile = "Dockerfile"   // FROM golang:<tag>
+	KindImage      = "golang image" // golang:<tag> image of a CI job
+)
+
+var (
+	// versionRegex matches the X.Y or X.Y.Z, with any rc or beta suffix, at
+	// the start of a value
+	versionRegex = regexp.MustCompile(`^v?(\d+\.\d+(?:\.\d+)?(?:rc\d+|beta\d+)?)`)
+	// goVersionKeyRegex matches a go-version key, e.g. in setup-go inputs or a matrix
+	goVersionKeyRegex = regexp.MustCompile(`^\s*(?:-\s+)?go-version:\s*(.*)$`)
+	// imageRegex matches a golang image reference and captures its tag
+	imageRegex = regexp.MustCompile(`(?:^|[\s"'/=])golang:([^\s"']+)`)
+	// fromRegex matches a Dockerfile FROM line and captures the image
+	fromRegex = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--\S+\s+)*(\S+)`)
+	// argRegex matches a Dockerfile ARG with a default value
+	argRegex = regexp.MustCompile(`(?i)^\s*ARG\s+(\w+)=["']?([^"'\s]+)`)
+	// actionsRefRegex matches a ${{ env.NAME }} or ${{ matrix.NAME }} reference
+	actionsRefRegex = regexp.MustCompile(`\$\{\{\s*(?:env|matrix)\.([\w-]+)\s*\}\}`)
+	// shellRefRegex matches a $NAME or ${NAME} reference
+	shellRefRegex = regexp.MustCompile(`\$\{?(\w+)\}?`)
+)
+
+// Declaration is a Go version found in a file
+type Declaration struct {
+	Kind    string `json:"kind"`    // One of the Kind* constants
+	File    string `json:"file"`    // Relative to the linted directory, with forward slashes
+	Line    int    `json:"line"`    // 1-based
+	Value   string `json:"value"`   // As written
+	Version string `json:"version"` // X.Y or X.Y.Z, or a pre-release like 1.23rc1
+}
+
+// Location returns "file:line"
+func (d Declaration) Location() string {
+	return d.File + ":" + strconv.Itoa(d.Line)
+}
+
+// collector gathers declarations from the files of one directory tree
+type collector struct {
+	root  string
+	decls []Declaration
+	seen  map[string]bool // file:line:version, values reached twice are kept once
+}
+
+// add records a declaration if value starts with a version
+func (c *collector) add(kind, path string, line int, value string) {
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	m := versionRegex.FindStringSubmatch(value)
+	if m == nil {
+		return // stable, oldstable, latest, unresolved references...
+	}
+
+	rel, err := filepath.Rel(c.root, path)
+	if err != nil {
+		rel = path
+	}
+	d := Declaration{Kind: kind, File: filepath.ToSlash(rel), Line: line, Value: value, Version: m[1]}
+	if key := d.Location() + ":" + d.Version; !c.seen[key] {
+		c.seen[key] = true
+		c.decls = append(c.decls, d)
+	}
+}
+
+// goFile records the version a go.mod or go.work file asks for, read as
+// version detection does: the toolchain line when it is newer than the go
+// line, otherwise the go line
+func (c *collector) goFile(kind, path string) bool {
+	directives, err := version.ParseGoDirectives(path)
+	if err != nil {
+		return false // Missing, or without a go or toolchain line
+	}
+	line := directives.GoLine
+	if directives.Version() != directives.Go {
+		line = directives.ToolchainLine
+	}
+	c.add(kind, path, line, directives.Version())
+	return true
+}
+
+// goVersionPin records the version of a .go-version file
+func (c *collector) goVersionPin(path string) bool {
+	lines, ok := readLines(path)
+	if !ok {
+		return false
+	}
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		before := len(c.decls)
+		c.add(KindGoVersion, path, i+1, strings.TrimPrefix(line, "go"))
+		return len(c.decls) > before
+	}
+	return false
+}
+
+// workflow records the go-version inputs and golang images of a GitHub
+// Actions workflow. ${{ env.NAME }} and ${{ matrix.NAME }} are resolved
+// from a NAME key in the same file.
+func (c *collector) workflow(path string) {
+	lines, ok := readLines(path)
+	if !ok {
+		return
+	}
+	for i, line := range lines {
+		line = stripYAMLComment(line)
+		if m := goVersionKeyRegex.FindStringSubmatch(line); m != nil {
+			for _, v := range yamlValues(lines, i, m[1]) {
+				c.addResolved(KindSetupGo, path, lines, v, actionsRefRegex)
+			}
+		}
+		if m := imageRegex.FindStringSubmatch(line); m != nil {
+			c.addResolved(KindImage, path, lines, lineValue{i, m[1]}, actionsRefRegex)
+		}
+	}
+}
+
+// gitlabCI records the golang images of a .gitlab-ci.yml. $NAME and ${NAME}
+// are resolved from a NAME key, usually under variables.
+func (c *collector) gitlabCI(path string) {
+	lines, ok := readLines(path)
+	if !ok {
+		return
+	}
+	for i, line := range lines {
+		if m := imageRegex.FindStringSubmatch(stripYAMLComment(line)); m != nil {
+			c.addResolved(KindImage, path, lines, lineValue{i, m[1]}, shellRefRegex)
+		}
+	}
+}
+
+// addResolved records a value, replacing a reference to a YAML key with the
+// key's values
+func (c *collector) addResolved(kind, path string, lines []string, v lineValue, ref *regexp.Regexp) {
+	m := ref.FindStringSubmatchIndex(v.value)
+	if m == nil {
+		c.add(kind, path, v.line+1, v.value)
+		return
+	}
+	name := v.value[m[2]:m[3]]
+	for _, r := range yamlKey(lines, name) {
+		if strings.Contains(r.value, "$") {
+			continue // The reference itself, or another one
+		}
+		c.add(kind, path, r.line+1, v.value[:m[0]]+r.value+v.value[m[1]:])
+	}
+}
+
+// dockerfile records the golang base images of a Dockerfile. ${NAME} in the
+// tag is resolved from an ARG default, which is then the reported line.
+func (c *collector) dockerfile(path string) {
+	lines, ok := readLines(path)
+	if !ok {
+		return
+	}
+
+	args := make(map[string]lineValue)
+	for i, line := range lines {
+		if m := argRegex.FindStringSubmatch(line); m != nil {
+			args[m[1]] = lineValue{i, m[2]}
+			continue
+		}
+		from := fromRegex.FindStringSubmatch(line)
+		if from == nil {
+			continue
+		}
+		image := imageRegex.FindStringSubmatch(" " + from[1])
+		if image == nil {
+			continue
+		}
+
+		tag, at := image[1], i
+		if m := shellRefRegex.FindStringSubmatchIndex(tag); m != nil {
+			arg, ok := args[tag[m[2]:m[3]]]
+			if !ok {
+				continue
+			}
+			tag, at = tag[:m[0]]+arg.value+tag[m[1]:], arg.line
+		}
+		c.add(KindDockerfile, path, at+1, tag)
+	}
+}
+
+// lineValue is a value and the 0-based line it was found on
+type lineValue struct {
+	line  int
+	value string
+}
+
+// yamlValues returns the values of the key on line i whose inline value is
+// rest: a scalar, a flow list ([a, b]) or, when rest is empty, the block
+// list items on the following lines
+func yamlValues(lines []string, i int, rest string) []lineValue {
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "[") {
+		var values []lineValue
+		for _, item := range strings.Split(strings.Trim(rest, "[]"), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, lineValue{i, item})
+			}
+		}
+		return values
+	}
+	if rest != "" && rest != "|" && rest != ">" {
+		return []lineValue{{i, rest}}
+	}
+
+	var values []lineValue
+	for j := i + 1; j < len(lines); j++ {
+		item := strings.TrimSpace(stripYAMLComment(lines[j]))
+		if item == "" {
+			continue
+		}
+		if !strings.HasPrefix(item, "- ") {
+			break
+		}
+		values = append(values, lineValue{j, strings.TrimSpace(item[2:])})
+	}
+	return values
+}
+
+// yamlKey returns the values of every key called name
+func yamlKey(lines []string, name string) []lineValue {
+	key := regexp.MustCompile(`^\s*(?:-\s+)?` + regexp.QuoteMeta(name) + `:\s*(.*)$`)
+	var values []lineValue
+	for i, line := range lines {
+		if m := key.FindStringSubmatch(stripYAMLComment(line)); m != nil {
+			values = append(values, yamlValues(lines, i, m[1])...)
+		}
+	}
+	return values
+}
+
+// stripYAMLComment removes a trailing # comment
+func stripYAMLComment(line string) string {
+	if i := strings.Index(line, " #"); i >= 0 {
+		return line[:i]
+	}
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	return line
+}
+
+// isDockerfile reports whether a file name is a Dockerfile
+func isDockerfile(name string) bool {
+	return name == "Dockerfile" || name == "Containerfile" ||
+		strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile")
+}
+
+// workflowFiles returns the GitHub Actions workflows of root
+func workflowFiles(root string) []string {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, _ := filepath.Glob(filepath.Join(root, ".github", "workflows", pattern))
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// dockerfiles returns the Dockerfiles under root that no .gitignore ignores
+func dockerfiles(root string) ([]string, error) {
+	return scan.Files(root, isDockerfile)
+}
+
+// readLines reads a file, reporting false if it does not exist
+func readLines(path string) ([]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), true
+}
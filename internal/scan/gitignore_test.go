@@ -0,0 +1,116 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIgnored(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	rules := func(dir string, lines ...string) *ignoreFile {
+		f := &ignoreFile{dir: filepath.Join(root, filepath.FromSlash(dir))}
+		for _, line := range lines {
+			if rule, ok := parseIgnoreRule(line); ok {
+				f.rules = append(f.rules, rule)
+			}
+		}
+		return f
+	}
+
+	top := rules("", "# comment", "", "*.log", "build/", "/vendor", "docs/**/draft", "!keep.log", `\#notes`, "tmp?")
+	nested := rules("svc", "*.gen", "!important.log")
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"app.log", false, true},
+		{"deep/dir/app.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false}, // build/ only matches directories
+		{"src/build", true, true},
+		{"vendor", true, true},
+		{"src/vendor", true, false}, // Anchored to the .gitignore directory
+		{"docs/draft", false, true},
+		{"docs/a/b/draft", false, true},
+		{"#notes", false, true},
+		{"tmp1", false, true},
+		{"tmp12", false, false},
+		{"main.go", false, false},
+		{"svc/api.gen", false, true},
+		{"api.gen", false, false}, // Nested rules only apply below their directory
+		{"svc/important.log", false, false},
+		{"svc/other.log", false, true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(root, filepath.FromSlash(tt.path))
+		if got := ignored([]*ignoreFile{top, nested}, path, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob string
+		want string
+	}{
+		{"*.go", `[^/]*\.go`},
+		{"a?c", `a[^/]c`},
+		{"**/x", `(?:.*/)?x`},
+		{"x/**", `x/.*`},
+		{"[!a]b", `[^a]b`},
+		{"[abc", `\[abc`},
+	}
+	for _, tt := range tests {
+		if got := globToRegexp(tt.glob); got != tt.want {
+			t.Errorf("globToRegexp(%q) = %q, want %q", tt.glob, got, tt.want)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		".gitignore":               "ignored/\n",
+		"go.mod":                   "module a\n",
+		"sub/go.mod":               "module b\n",
+		"sub/.gitignore":           "gen/go.mod\n",
+		"sub/gen/go.mod":           "module c\n",
+		"ignored/go.mod":           "module d\n",
+		"vendor/x/go.mod":          "module e\n",
+		"node_modules/y/go.mod":    "module f\n",
+		"pkg/testdata/mod/go.mod":  "module g\n",
+		"other/nested/deep/go.mod": "module h\n",
+	} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Files(root, func(name string) bool { return name == "go.mod" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	slices.Sort(got)
+
+	want := []string{"go.mod", "other/nested/deep/go.mod", "sub/go.mod"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Files = %s, want %s", strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+}
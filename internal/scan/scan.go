@@ -0,0 +1,102 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// skipDirs are never searched: dependency trees, and test fixtures the go
+// command ignores too
+var skipDirs = map[string]bool{
+	".git":         true,
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+}
+
+// Project is a directory with a version file
+type Project struct {
+	Dir     string // Absolute path
+	Version string // Required version as declared, e.g. "1.22" or "1.22.5"
+	Source  string // Version file the version was read from
+}
+
+// Projects walks root and returns every directory that declares a Go
+// version, as its version files declare it regardless of GOTOOLCHAIN,
+// sorted by path.
+// Directories ignored by a .gitignore are skipped, and so are .git,
+// vendor, node_modules and testdata. Symbolic links are not followed.
+func Projects(root string) ([]Project, error) {
+	var projects []Project
+	err := walkRoot(root, func(dir string, files []string) {
+		if ver, source, err := version.DeclaredVersionInDir(dir); err == nil {
+			projects = append(projects, Project{Dir: dir, Version: ver, Source: source})
+		}
+	})
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Dir < projects[j].Dir })
+	return projects, err
+}
+
+// Files walks root like Projects and returns the paths of the files whose
+// name satisfies match and that no .gitignore ignores, sorted
+func Files(root string, match func(name string) bool) ([]string, error) {
+	var paths []string
+	err := walkRoot(root, func(dir string, files []string) {
+		for _, name := range files {
+			if match(name) {
+				paths = append(paths, filepath.Join(dir, name))
+			}
+		}
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// walkRoot calls visit for root and every directory below it that is not
+// skipped, with the names of its files that are not ignored
+func walkRoot(root string, visit func(dir string, files []string)) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return err
+	}
+	walk(root, nil, visit)
+	return nil
+}
+
+// walk visits dir and its subdirectories. Unreadable directories are skipped.
+func walk(dir string, ignores []*ignoreFile, visit func(dir string, files []string)) {
+	if f := readIgnoreFile(dir); f != nil {
+		ignores = append(ignores[:len(ignores):len(ignores)], f)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var files, dirs []string
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		switch {
+		case e.IsDir():
+			if !skipDirs[e.Name()] && !ignored(ignores, path, true) {
+				dirs = append(dirs, path)
+			}
+		case e.Type().IsRegular():
+			if !ignored(ignores, path, false) {
+				files = append(files, e.Name())
+			}
+		}
+	}
+
+	visit(dir, files)
+	for _, d := range dirs {
+		walk(d, ignores, visit)
+	}
+}
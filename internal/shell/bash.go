@@ -11,71 +11,330 @@ export GOVM_ROOT="${GOVM_ROOT:-$HOME/.govm}"
 [[ ":$PATH:" != *":$GOVM_ROOT/bin:"* ]] && export PATH="$GOVM_ROOT/bin:$PATH"
 [[ ":$PATH:" != *":$GOVM_ROOT/current/bin:"* ]] && export PATH="$GOVM_ROOT/current/bin:$PATH"
 
-# Set GOROOT to current version
+# Set GOROOT to the current version (a session version overrides it below)
 export GOROOT="$GOVM_ROOT/current"
 
 # Add GOPATH/bin to PATH for go install packages
 export GOPATH="${GOPATH:-$HOME/go}"
 [[ ":$PATH:" != *":$GOPATH/bin:"* ]] && export PATH="$GOPATH/bin:$PATH"
 
-# Auto-switch Go version based on go.mod/go.work
+# Module cache shared by all versions when GOPATH is isolated per version
+_GOVM_SHARED_MODCACHE="${GOMODCACHE:-${GOPATH%%:*}/pkg/mod}"
+
+# Atomically point $GOVM_ROOT/current at a Go installation: create the
+# symlink under a temporary name, then rename it over the old one
+_govm_link() {
+    local tmp="$GOVM_ROOT/.current.$$"
+    ln -sfn "$1" "$tmp" 2>/dev/null || return 1
+    # GNU mv uses -T, BSD/macOS mv uses -h to replace the symlink itself
+    mv -Tf "$tmp" "$GOVM_ROOT/current" 2>/dev/null ||
+        mv -hf "$tmp" "$GOVM_ROOT/current" 2>/dev/null || {
+        rm -f "$tmp"
+        return 1
+    }
+}
+
+# Remove the entries below a govm-managed directory from PATH
+_govm_strip_path() {
+    local entry new="" IFS=:
+    for entry in $PATH; do
+        [[ "$entry" == "$1/"* ]] && continue
+        new="${new:+$new:}$entry"
+    done
+    PATH="$new"
+}
+
+# Activate a version for this shell only by putting it first in PATH
+_govm_session_use() {
+    _govm_strip_path "$GOVM_ROOT/versions"
+    export PATH="$GOVM_ROOT/versions/$1/go/bin:$PATH"
+    export GOROOT="$GOVM_ROOT/versions/$1/go"
+}
+
+# A session version inherited from the environment, e.g. in a
+# 'govm shell --subshell' whose rc file runs init, goes before current/bin
+if [[ -n "${GOVM_SHELL_VERSION:-$GOVM_AUTO_VERSION}" ]]; then
+    _govm_session_use "${GOVM_SHELL_VERSION:-$GOVM_AUTO_VERSION}"
+fi
+
+# Activate a version in the configured switch scope
+_govm_activate() {
+    if [[ "$GOVM_SWITCH_SCOPE" == "session" ]]; then
+        export GOVM_AUTO_VERSION="$1"
+        _govm_session_use "$1"
+    else
+        _govm_link "$GOVM_ROOT/versions/$1/go"
+    fi
+    _govm_isolate "$1"
+    _govm_apply_env "$1"
+}
+
+# Point GOBIN, GOPATH and GOENV at per-version directories under
+# $GOVM_ROOT/envs according to the isolate setting
+_govm_isolate() {
+    [[ -n "$GOVM_ISOLATE" && -n "$1" ]] || return 0
+    local dir="$GOVM_ROOT/envs/$1" tools=""
+    if [[ ",$GOVM_ISOLATE," == *,gopath,* ]]; then
+        # The module cache stays shared unless modcache is isolated too
+        [[ ",$GOVM_ISOLATE," == *,modcache,* ]] || export GOMODCACHE="$_GOVM_SHARED_MODCACHE"
+        export GOPATH="$dir/gopath"
+        tools="$GOPATH/bin"
+    fi
+    if [[ ",$GOVM_ISOLATE," == *,gobin,* ]]; then
+        export GOBIN="$dir/bin"
+        tools="$GOBIN"
+    fi
+    [[ ",$GOVM_ISOLATE," == *,goenv,* ]] && export GOENV="$dir/go.env"
+    # Replace the previous version's tools directory in PATH
+    _govm_strip_path "$GOVM_ROOT/envs"
+    [[ -n "$tools" ]] && export PATH="$tools:$PATH"
+    return 0
+}
+
+# Apply the [versions."<spec>"] env of config.toml for a version and unset
+# variables configured only for other versions. Matching specs needs govm,
+# so it only runs when per-version variables are configured.
+_govm_apply_env() {
+    [[ "$GOVM_VERSION_ENV" == "true" && -n "$1" && "$1" != "$_GOVM_ENV_VERSION" ]] || return 0
+    local code
+    code="$(command govm env --hook "$1" 2>/dev/null)" || return 0
+    eval "$code"
+    _GOVM_ENV_VERSION="$1"
+}
+
+# Print the newest installed version matching an exact or partial version,
+# the same choice as SelectInstalled in Go
+_govm_find_installed() {
+    if [[ "$1" == *.*.* ]]; then
+        [[ -d "$GOVM_ROOT/versions/$1" ]] && echo "$1"
+    else
+        # Newest of 1.20 itself and every 1.20.x; whole segments only, so
+        # 1.2 must not pick 1.21.x
+        ls -1 "$GOVM_ROOT/versions" 2>/dev/null | awk -v p="$1" '$0 == p || index($0, p ".") == 1' | sort -V | tail -1
+    fi
+}
+
+# Print the version active in this shell (session-scope version first, then the symlink)
+_govm_active_version() {
+    if [[ -n "$GOVM_AUTO_VERSION" ]]; then
+        echo "$GOVM_AUTO_VERSION"
+    elif [[ -L "$GOVM_ROOT/current" ]]; then
+        basename "$(dirname "$(readlink "$GOVM_ROOT/current")")"
+    fi
+}
+
+# Restore a version after leaving a project, according to GOVM_ON_LEAVE:
+# "default" switches to default_version, "previous" to the version active
+# before entering the project, "keep" leaves the project version active
+_govm_leave_project() {
+    [[ -z "$_GOVM_IN_PROJECT" ]] && return
+    _GOVM_IN_PROJECT=""
+
+    local target="" reason=""
+    case "${GOVM_ON_LEAVE:-default}" in
+        default)
+            target=$(sed -n "s/^default_version *= *[\"']\(.*\)[\"'].*/\1/p" "$GOVM_ROOT/config.toml" 2>/dev/null)
+            reason="default"
+            ;;
+        previous)
+            target="$_GOVM_PREVIOUS_VERSION"
+            reason="previous"
+            ;;
+        *)
+            return
+            ;;
+    esac
+
+    [[ -z "$target" ]] && return
+    target="$(_govm_find_installed "$target")"
+    [[ -z "$target" || "$target" == "$(_govm_active_version)" ]] && return
+
+    _govm_activate "$target"
+    echo -e "\033[0;36mgovm:\033[0m restored Go $target ($reason)"
+}
+
+# Wrap govm so 'govm shell' can change the environment of this shell
+govm() {
+    if [[ "$1" == "shell" && $# -eq 2 ]]; then
+        local code
+        case "$2" in
+            -u|--unset)
+                code="$(command govm shell --eval --unset)" || return
+                eval "$code"
+                _GOVM_ENV_VERSION=""
+                _govm_auto_switch
+                return
+                ;;
+            -*) ;;
+            *)
+                code="$(command govm shell --eval "$2")" || return
+                eval "$code"
+                _GOVM_ENV_VERSION=""
+                return
+                ;;
+        esac
+    fi
+    command govm "$@"
+}
+
+# Succeed if version $1 is newer than version $2 (X.Y counts as X.Y.0)
+_govm_version_gt() {
+    local a="$1" b="$2"
+    [[ "$a" =~ ^[0-9]+\.[0-9]+$ ]] && a="$a.0"
+    [[ "$b" =~ ^[0-9]+\.[0-9]+$ ]] && b="$b.0"
+    [[ "$a" != "$b" && "$(printf '%s\n%s\n' "$a" "$b" | sort -V | tail -1)" == "$a" ]]
+}
+
+# Print the version a go.mod or go.work file asks for: the toolchain
+# directive when it is newer than the go directive
+_govm_mod_version() {
+    [[ -f "$1" ]] || return
+    local version toolchain
+    version=$(grep -E '^[[:space:]]*go[[:space:]]+[0-9]+\.[0-9]+' "$1" | head -1 | awk '{print $2}')
+    toolchain=$(grep -E '^[[:space:]]*toolchain[[:space:]]+go[0-9]+\.[0-9]+' "$1" | head -1 | awk '{print $2}')
+    toolchain="${toolchain#go}"
+    toolchain="${toolchain%%-*}"
+    if [[ -n "$toolchain" ]] && { [[ -z "$version" ]] || _govm_version_gt "$toolchain" "$version"; }; then
+        version="$toolchain"
+    fi
+    echo "$version"
+}
+
+# Print the module directories of a go.work file's use directives, one per
+# line, in single-line or block form
+_govm_work_uses() {
+    awk '
+        { sub(/\/\/.*/, "") }
+        $1 == "use" && $2 == "(" { block = 1; next }
+        $1 == "use" && NF > 1 { print $2; next }
+        block && $1 == ")" { block = 0; next }
+        block && NF { print $1 }
+    ' "$1" | tr -d '"\140'
+}
+
+# Print "<version> <file>" for the first version source in a directory.
+# Precedence: .go-version, .tool-versions, go.work, go.mod
+_govm_detect_in_dir() {
+    local dir="$1" file version module
+    for file in .go-version .tool-versions go.work go.mod; do
+        [[ -f "$dir/$file" ]] || continue
+        case "$file" in
+            .go-version)
+                version=$(grep -v -E '^[[:space:]]*(#|$)' "$dir/$file" | head -1 | tr -d '[:space:]')
+                ;;
+            .tool-versions)
+                version=$(sed 's/#.*//' "$dir/$file" | awk '$1 == "golang" || $1 == "go" { print $2; exit }')
+                ;;
+            go.work)
+                # The workspace needs the newest version any used module asks for
+                version=$(_govm_mod_version "$dir/$file")
+                while IFS= read -r module; do
+                    [[ -n "$module" ]] || continue
+                    [[ "$module" == /* ]] || module="$dir/$module"
+                    module=$(_govm_mod_version "$module/go.mod")
+                    if [[ -n "$module" ]] && { [[ -z "$version" ]] || _govm_version_gt "$module" "$version"; }; then
+                        version="$module"
+                    fi
+                done <<< "$(_govm_work_uses "$dir/$file")"
+                ;;
+            go.mod)
+                version=$(_govm_mod_version "$dir/$file")
+                ;;
+        esac
+        version="${version#go}"
+        if [[ "$version" == [0-9]* ]]; then
+            echo "$version $dir/$file"
+            return 0
+        fi
+    done
+    return 1
+}
+
+# Print the parent directory the version search continues with after $1,
+# which is $2 levels above the start, or fail at a boundary. Same rules as
+# the Go detector: a .git repository root (searched), $HOME and ceiling
+# directories (not searched) and inherit_max_depth.
+_govm_next_search_dir() {
+    local dir="$1" depth="$2" parent ceiling
+    [[ ",$GOVM_INHERIT_STOP," == *,git,* && -e "$dir/.git" ]] && return 1
+    [[ "${GOVM_INHERIT_MAX_DEPTH:-0}" -gt 0 && "$depth" -ge "$GOVM_INHERIT_MAX_DEPTH" ]] && return 1
+    [[ "$dir" == / ]] && return 1
+    parent="$(dirname "$dir")"
+    [[ ",$GOVM_INHERIT_STOP," == *,home,* && "$parent" == "${HOME%/}" ]] && return 1
+    local IFS=:
+    for ceiling in $GOVM_CEILING_DIRECTORIES; do
+        [[ "$parent" == "$ceiling" ]] && return 1
+    done
+    echo "$parent"
+}
+
+# Auto-switch Go version based on .go-version, .tool-versions, go.work or go.mod
 _govm_auto_switch() {
-    local go_file=""
+    # A version set with 'govm shell' overrides project detection
+    [[ -n "$GOVM_SHELL_VERSION" ]] && return
+
+    local detected=""
     local search_dir="$PWD"
 
     # Check current directory first
-    if [[ -f "go.work" ]]; then
-        go_file="$PWD/go.work"
-    elif [[ -f "go.mod" ]]; then
-        go_file="$PWD/go.mod"
-    fi
+    detected="$(_govm_detect_in_dir "$PWD")"
 
-    # If inherit_version is enabled, search parent directories
-    if [[ -z "$go_file" && "$GOVM_INHERIT_VERSION" == "true" ]]; then
-        while [[ "$search_dir" != "/" ]]; do
-            search_dir="$(dirname "$search_dir")"
-            if [[ -f "$search_dir/go.work" ]]; then
-                go_file="$search_dir/go.work"
-                break
-            elif [[ -f "$search_dir/go.mod" ]]; then
-                go_file="$search_dir/go.mod"
-                break
-            fi
+    # If inherit_version is enabled, search parent directories up to the
+    # first boundary
+    if [[ -z "$detected" && "$GOVM_INHERIT_VERSION" == "true" ]]; then
+        local depth=0
+        while search_dir="$(_govm_next_search_dir "$search_dir" "$depth")"; do
+            depth=$((depth + 1))
+            detected="$(_govm_detect_in_dir "$search_dir")" && break
         done
     fi
 
-    if [[ -n "$go_file" ]]; then
-        # Extract version from file
-        local version=$(grep -E '^go [0-9]+\.[0-9]+' "$go_file" | head -1 | awk '{print $2}')
+    local version="${detected%% *}"
+    local go_file="${detected#* }"
 
-        if [[ -n "$version" ]]; then
-            # Get current version
-            local current=""
-            if [[ -L "$GOVM_ROOT/current" ]]; then
-                current=$(basename "$(dirname "$(readlink "$GOVM_ROOT/current")")")
-            fi
+    # GOTOOLCHAIN=goX.Y.Z pins a version, goX.Y.Z+auto/+path sets a minimum
+    if [[ "$GOTOOLCHAIN" == go[0-9]* ]]; then
+        local gotoolchain="${GOTOOLCHAIN%%+*}"
+        gotoolchain="${gotoolchain#go}"
+        gotoolchain="${gotoolchain%%-*}"
+        if [[ "$GOTOOLCHAIN" != *+* || -z "$version" ]] || _govm_version_gt "$gotoolchain" "$version"; then
+            version="$gotoolchain"
+            go_file="GOTOOLCHAIN"
+        fi
+    fi
 
-            # Check if we need to switch (handle both X.Y and X.Y.Z formats)
-            if [[ "$current" != "$version"* ]]; then
-                # Try to find exact or compatible version
-                local target_version=""
-                if [[ -d "$GOVM_ROOT/versions/$version" ]]; then
-                    target_version="$version"
-                else
-                    # Find latest patch version
-                    target_version=$(ls -1 "$GOVM_ROOT/versions" 2>/dev/null | grep "^${version}" | sort -V | tail -1)
-                fi
-
-                if [[ -n "$target_version" && -d "$GOVM_ROOT/versions/$target_version" ]]; then
-                    # Switch version silently
-                    ln -sfn "$GOVM_ROOT/versions/$target_version/go" "$GOVM_ROOT/current" 2>/dev/null
-                    echo -e "\033[0;36mgovm:\033[0m switched to Go $target_version (from $go_file)"
-                elif command -v govm &>/dev/null; then
-                    # Version not installed, use govm use which auto-installs if enabled
-                    echo -e "\033[0;33mgovm:\033[0m Go $version required (from $go_file), installing..."
-                    govm use "$version"
-                fi
-            fi
+    # No version source here: we left a project (or never entered one)
+    if [[ -z "$version" ]]; then
+        _govm_leave_project
+        return
+    fi
+
+    # Remember what was active before entering the project
+    if [[ -z "$_GOVM_IN_PROJECT" ]]; then
+        _GOVM_PREVIOUS_VERSION="$(_govm_active_version)"
+        _GOVM_IN_PROJECT=1
+    fi
+
+    local current="$(_govm_active_version)"
+
+    # Newest installed match, the same version 'govm resolve' reports
+    local target_version="$(_govm_find_installed "$version")"
+
+    if [[ -n "$target_version" ]]; then
+        if [[ "$target_version" != "$current" ]]; then
+            _govm_activate "$target_version"
+            echo -e "\033[0;36mgovm:\033[0m switched to Go $target_version (from $go_file)"
+        fi
+    elif command -v govm &>/dev/null && [[ "$GOTOOLCHAIN" != path && "$GOTOOLCHAIN" != *+path ]]; then
+        # Version not installed, use govm which auto-installs if enabled
+        # (GOTOOLCHAIN path mode never installs)
+        echo -e "\033[0;33mgovm:\033[0m Go $version required (from $go_file), installing..."
+        if [[ "$GOVM_SWITCH_SCOPE" == "session" ]]; then
+            command govm install "$version" &&
+                target_version="$(_govm_find_installed "$version")" &&
+                [[ -n "$target_version" ]] && _govm_activate "$target_version"
+        else
+            command govm use "$version"
         fi
     fi
 }
@@ -90,6 +349,10 @@ alias cd='_govm_cd'
 
 # Run on shell startup for current directory
 _govm_auto_switch
+if [[ -z "$GOVM_SHELL_VERSION" ]]; then
+    _govm_isolate "$(_govm_active_version)"
+    _govm_apply_env "$(_govm_active_version)"
+fi
 
 # Completions
 _govm_completions() {
@@ -101,7 +364,7 @@ _govm_completions() {
             # Complete with remote versions (cached)
             COMPREPLY=()
             ;;
-        uninstall|rm|remove|delete|use|switch|select|exec)
+        uninstall|rm|remove|delete|use|switch|select|shell|pin|exec|env)
             # Complete with installed versions
             if [[ -d "$GOVM_ROOT/versions" ]]; then
                 COMPREPLY=($(compgen -W "$(ls "$GOVM_ROOT/versions" 2>/dev/null)" -- "$cur"))
@@ -117,7 +380,7 @@ _govm_completions() {
             COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
             ;;
         *)
-            COMPREPLY=($(compgen -W "install uninstall use list alias exec current init upgrade version setup" -- "$cur"))
+            COMPREPLY=($(compgen -W "install uninstall use shell pin unpin list outdated update alias tools exec run matrix bisect bench env current resolve scan lint bump init upgrade version setup" -- "$cur"))
             ;;
     esac
 }
@@ -0,0 +1,261 @@
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// Output formats supported by Env.Format
+const (
+	FormatSh         = "sh"
+	FormatFish       = "fish"
+	FormatPowerShell = "powershell"
+	FormatDotenv     = "dotenv"
+	FormatJSON       = "json"
+	FormatMake       = "make"
+)
+
+// EnvFormats lists the output formats in the order they are documented
+var EnvFormats = []string{FormatSh, FormatFish, FormatPowerShell, FormatDotenv, FormatJSON, FormatMake}
+
+// EnvVar is a single environment variable. An empty Value with Unset set
+// removes the variable instead of setting it.
+type EnvVar struct {
+	Key   string
+	Value string
+	Unset bool
+}
+
+// Env is an ordered list of environment changes
+type Env []EnvVar
+
+// Set appends a variable assignment
+func (e *Env) Set(key, value string) {
+	*e = append(*e, EnvVar{Key: key, Value: value})
+}
+
+// Remove appends a variable removal
+func (e *Env) Remove(key string) {
+	*e = append(*e, EnvVar{Key: key, Unset: true})
+}
+
+// Get returns the value assigned to key, if any
+func (e Env) Get(key string) (string, bool) {
+	for _, v := range e {
+		if v.Key == key && !v.Unset {
+			return v.Value, true
+		}
+	}
+	return "", false
+}
+
+// Apply returns environ (KEY=value entries) with the changes applied
+func (e Env) Apply(environ []string) []string {
+	result := make([]string, 0, len(environ)+len(e))
+	result = append(result, environ...)
+
+	for _, v := range e {
+		prefix := v.Key + "="
+		found := false
+		for i := 0; i < len(result); i++ {
+			if !strings.HasPrefix(result[i], prefix) {
+				continue
+			}
+			if v.Unset {
+				result = append(result[:i], result[i+1:]...)
+				i--
+				continue
+			}
+			result[i] = prefix + v.Value
+			found = true
+		}
+		if !found && !v.Unset {
+			result = append(result, prefix+v.Value)
+		}
+	}
+	return result
+}
+
+func init() {
+	version.ToolEnviron = toolEnviron
+}
+
+// toolEnviron returns the environment 'go install' runs in: the version's
+// environment, with GOTOOLCHAIN=local so the toolchain never switches
+func toolEnviron(ver string) ([]string, error) {
+	env, err := VersionEnv(ver)
+	if err != nil {
+		return nil, err
+	}
+	env.Set("GOTOOLCHAIN", "local")
+	return env.Apply(os.Environ()), nil
+}
+
+// VersionEnv returns the environment that activates version: GOROOT, a
+// PATH with the version's bin directory (and isolated tools directory) in
+// front of any other govm-managed entry, the isolated GOBIN/GOPATH/GOENV and
+// the variables configured for the version in config.toml
+func VersionEnv(ver string) (Env, error) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	goRoot := filepath.Join(paths.VersionPath(ver), "go")
+	return activeEnv(ver, goRoot, filepath.Join(goRoot, "bin"), paths), nil
+}
+
+// activeEnv builds the environment for ver with the given GOROOT, putting
+// binDir (if not empty) first in PATH
+func activeEnv(ver, goRoot, binDir string, paths *config.Paths) Env {
+	isolation, toolsBin := IsolationEnv(ver)
+
+	pathList := stripVersionPaths(os.Getenv("PATH"), paths)
+	if toolsBin != "" {
+		pathList = joinPath(toolsBin, pathList)
+	}
+
+	var env Env
+	env.Set("GOROOT", goRoot)
+	if binDir != "" {
+		pathList = joinPath(binDir, pathList)
+	}
+	env.Set("PATH", pathList)
+	env = append(env, isolation...)
+	return append(env, ConfiguredEnv(ver)...)
+}
+
+// ConfiguredEnv returns the [versions."<spec>"] variables for ver. Variables
+// configured only for other versions are removed, so switching never leaves
+// stale values behind.
+func ConfiguredEnv(ver string) Env {
+	vars := version.ConfiguredEnv(ver)
+
+	var env Env
+	for _, key := range version.ConfiguredEnvKeys() {
+		if value, ok := vars[key]; ok {
+			env.Set(key, value)
+		} else {
+			env.Remove(key)
+		}
+	}
+	return env
+}
+
+// DeactivateEnv returns the environment that undoes VersionEnv: GOROOT,
+// isolation and configured variables are removed and govm-managed
+// directories are dropped from PATH
+func DeactivateEnv() (Env, error) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var env Env
+	env.Remove("GOROOT")
+	env.Set("PATH", stripVersionPaths(os.Getenv("PATH"), paths))
+	env = append(env, isolationUnset()...)
+	for _, key := range version.ConfiguredEnvKeys() {
+		env.Remove(key)
+	}
+	return env, nil
+}
+
+// Format renders the environment in one of the EnvFormats
+func (e Env) Format(format string) (string, error) {
+	var b strings.Builder
+
+	switch format {
+	case FormatSh:
+		for _, v := range e {
+			if v.Unset {
+				fmt.Fprintf(&b, "unset %s\n", v.Key)
+			} else {
+				fmt.Fprintf(&b, "export %s=%s\n", v.Key, Quote(v.Value))
+			}
+		}
+
+	case FormatFish:
+		for _, v := range e {
+			switch {
+			case v.Unset:
+				fmt.Fprintf(&b, "set -e %s\n", v.Key)
+			case v.Key == "PATH":
+				// fish keeps PATH as a list, one element per directory
+				fmt.Fprintf(&b, "set -gx PATH")
+				for _, dir := range filepath.SplitList(v.Value) {
+					fmt.Fprintf(&b, " %s", fishQuote(dir))
+				}
+				b.WriteString("\n")
+			default:
+				fmt.Fprintf(&b, "set -gx %s %s\n", v.Key, fishQuote(v.Value))
+			}
+		}
+
+	case FormatPowerShell:
+		for _, v := range e {
+			if v.Unset {
+				fmt.Fprintf(&b, "Remove-Item Env:%s -ErrorAction SilentlyContinue\n", v.Key)
+			} else {
+				fmt.Fprintf(&b, "$env:%s = '%s'\n", v.Key, strings.ReplaceAll(v.Value, "'", "''"))
+			}
+		}
+
+	case FormatDotenv:
+		for _, v := range e {
+			// dotenv has no unset, an empty value is the closest equivalent
+			value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(v.Value)
+			fmt.Fprintf(&b, "%s=\"%s\"\n", v.Key, value)
+		}
+
+	case FormatJSON:
+		obj := make(map[string]*string, len(e))
+		for _, v := range e {
+			if v.Unset {
+				obj[v.Key] = nil
+			} else {
+				value := v.Value
+				obj[v.Key] = &value
+			}
+		}
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(data)
+		b.WriteString("\n")
+
+	case FormatMake:
+		for _, v := range e {
+			if v.Unset {
+				fmt.Fprintf(&b, "unexport %s\n", v.Key)
+			} else {
+				fmt.Fprintf(&b, "export %s := %s\n", v.Key, strings.ReplaceAll(v.Value, "$", "$$"))
+			}
+		}
+
+	default:
+		return "", fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(EnvFormats, ", "))
+	}
+
+	return b.String(), nil
+}
+
+// joinPath prepends dir to a PATH list
+func joinPath(dir, pathList string) string {
+	if pathList == "" {
+		return dir
+	}
+	return dir + string(os.PathListSeparator) + pathList
+}
+
+// fishQuote quotes a string for fish, which only escapes \ and ' inside single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,63 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// hermeticAllowed lists the variables a hermetic environment keeps from the
+// caller: identity, locale, terminal and network settings that do not change
+// what the go command builds
+var hermeticAllowed = []string{
+	"HOME", "USER", "LOGNAME", "SHELL", "TERM", "TMPDIR", "TZ",
+	"LANG", "LC_ALL", "LC_CTYPE", "LC_MESSAGES",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR",
+	"GOPROXY", "GOPRIVATE", "GONOPROXY", "GONOSUMDB", "GOSUMDB", "GOINSECURE",
+	"GOVM_ROOT",
+}
+
+// hermeticCaches lists the cache locations kept from the caller when no
+// separate cache directory is used
+var hermeticCaches = []string{"GOCACHE", "GOMODCACHE", "GOPATH"}
+
+// HermeticEnv returns the complete environment for running ver in isolation
+// from the caller: the allow-listed variables, GOROOT, a PATH with the
+// version's bin directory in front of the system directories, and
+// GOTOOLCHAIN=local and GOENV=off so neither a toolchain switch nor
+// 'go env -w' settings can interfere. Configured [versions] variables and
+// isolate settings are not applied. A non-empty cacheDir holds GOCACHE
+// (build) and GOMODCACHE (mod); with an empty one the caller's GOCACHE,
+// GOMODCACHE and GOPATH are kept, so the regular caches are shared.
+func HermeticEnv(ver, cacheDir string) (Env, error) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var env Env
+	for _, key := range hermeticAllowed {
+		if value, ok := os.LookupEnv(key); ok {
+			env.Set(key, value)
+		}
+	}
+
+	goRoot := filepath.Join(paths.VersionPath(ver), "go")
+	env.Set("GOROOT", goRoot)
+	env.Set("PATH", joinPath(filepath.Join(goRoot, "bin"), stripVersionPaths(os.Getenv("PATH"), paths)))
+	env.Set("GOTOOLCHAIN", "local")
+	env.Set("GOENV", "off")
+	if cacheDir != "" {
+		env.Set("GOCACHE", filepath.Join(cacheDir, "build"))
+		env.Set("GOMODCACHE", filepath.Join(cacheDir, "mod"))
+	} else {
+		for _, key := range hermeticCaches {
+			if value, ok := os.LookupEnv(key); ok {
+				env.Set(key, value)
+			}
+		}
+	}
+	return env, nil
+}
@@ -9,27 +9,39 @@ import (
 )
 
 // AutoSwitch performs automatic version switching based on go.mod/go.work
-// This is called by shell hooks when changing directories
-func AutoSwitch(dir string) (string, bool, error) {
-	// Detect version from go.mod/go.work
-	ver, _, err := version.DetectVersionInDir(dir)
-	if err != nil {
-		return "", false, nil // No go.mod/go.work, not an error
-	}
-
-	// Normalize version
-	fullVersion, err := version.NormalizeDetectedVersion(ver)
-	if err != nil {
-		fullVersion = ver
-	}
-
+// This is called by shell hooks when changing directories.
+// previous is the version that was active before entering the current
+// project; it is restored when dir has no version source and on_leave is
+// "previous".
+func AutoSwitch(dir, previous string) (string, bool, error) {
 	// Get current version
 	mgr, err := version.NewManager()
 	if err != nil {
 		return "", false, err
 	}
-
 	current, _ := mgr.Current()
+
+	// Detect the version as shims and 'govm resolve' do, so inheritance,
+	// boundaries and GOTOOLCHAIN apply
+	var fullVersion string
+	res, err := version.Resolve(dir)
+	switch {
+	case err == nil && res.Origin == config.EnvShellVersion:
+		// 'govm shell' overrides auto-switch in this shell
+		return current, false, nil
+	case err == nil && res.Source == version.SourceProject:
+		fullVersion = res.Version
+		if fullVersion == "" {
+			fullVersion = res.Raw // Not installed, QuietUse installs it
+		}
+	default:
+		// No version source: apply the on_leave setting
+		fullVersion = leaveTarget(previous)
+		if fullVersion == "" {
+			return current, false, nil
+		}
+	}
+
 	if current == fullVersion {
 		return fullVersion, false, nil // Already using correct version
 	}
@@ -42,6 +54,19 @@ func AutoSwitch(dir string) (string, bool, error) {
 	return fullVersion, true, nil
 }
 
+// leaveTarget returns the version to restore after leaving a project
+func leaveTarget(previous string) string {
+	cfg := config.Get()
+	switch cfg.OnLeave {
+	case config.LeaveDefault:
+		return cfg.DefaultVersion
+	case config.LeavePrevious:
+		return previous
+	default:
+		return ""
+	}
+}
+
 // GetGovmBin returns the path to the govm binary directory
 func GetGovmBin() string {
 	paths, err := config.GetPaths()
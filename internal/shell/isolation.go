@@ -0,0 +1,35 @@
+package shell
+
+import (
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// IsolationEnv returns GOBIN, GOPATH, GOENV and GOMODCACHE for ver according
+// to the isolate setting, and the directory that holds the version's
+// installed tools ("" when tools are not isolated)
+func IsolationEnv(ver string) (Env, string) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return nil, ""
+	}
+
+	vars, toolsBin := paths.Isolation(config.Get(), ver)
+	var env Env
+	for _, v := range vars {
+		env.Set(v.Key, v.Value)
+	}
+	return env, toolsBin
+}
+
+// isolationUnset returns removals for every variable IsolationEnv may set
+func isolationUnset() Env {
+	if len(config.Get().Isolate) == 0 {
+		return nil
+	}
+
+	var env Env
+	for _, key := range []string{"GOBIN", "GOPATH", "GOENV", "GOMODCACHE"} {
+		env.Remove(key)
+	}
+	return env
+}
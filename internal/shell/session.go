@@ -0,0 +1,69 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// SessionEnv returns the environment variables that activate ver for
+// a single shell session: GOVM_SHELL_VERSION plus everything VersionEnv sets
+func SessionEnv(ver string) (Env, error) {
+	env, err := VersionEnv(ver)
+	if err != nil {
+		return nil, err
+	}
+	return append(Env{{Key: config.EnvShellVersion, Value: ver}}, env...), nil
+}
+
+// SessionExports returns sh-compatible code that activates ver for the current shell
+func SessionExports(ver string) (string, error) {
+	env, err := SessionEnv(ver)
+	if err != nil {
+		return "", err
+	}
+	return env.Format(FormatSh)
+}
+
+// SessionUnset returns sh-compatible code that drops the session override and
+// goes back to the global current symlink, with its configured variables
+func SessionUnset() (string, error) {
+	paths, err := config.GetPaths()
+	if err != nil {
+		return "", err
+	}
+
+	current := ""
+	if installer, err := version.NewInstaller(); err == nil {
+		current, _ = installer.GetCurrent()
+	}
+
+	var env Env
+	env.Remove(config.EnvShellVersion)
+	env.Remove(config.EnvAutoVersion)
+	// current/bin is already in PATH from the shell integration
+	env = append(env, activeEnv(current, paths.Current, "", paths)...)
+
+	return env.Format(FormatSh)
+}
+
+// stripVersionPaths removes every entry below the versions and isolation
+// directories from a PATH list
+func stripVersionPaths(pathList string, paths *config.Paths) string {
+	var kept []string
+	for _, entry := range filepath.SplitList(pathList) {
+		if strings.HasPrefix(entry, paths.Versions+string(os.PathSeparator)) || paths.IsIsolated(entry) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return strings.Join(kept, string(os.PathListSeparator))
+}
+
+// Quote quotes a string for safe use in sh-compatible shells
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,84 @@
+package shim
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/shell"
+	"github.com/wenzzy/govm/internal/ui"
+	"github.com/wenzzy/govm/internal/version"
+)
+
+// IsShim reports whether govm was invoked through a shim in Paths.Bin
+// rather than as govm itself
+func IsShim(arg0 string) bool {
+	name := filepath.Base(arg0)
+	if name == "govm" || name == "g" || strings.HasPrefix(name, "govm") {
+		return false
+	}
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		return false
+	}
+	info, err := os.Lstat(filepath.Join(paths.Bin, name))
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// Run resolves the Go version for the working directory and replaces the
+// process with the named tool from that toolchain. It only returns on error.
+func Run(name string, args []string) error {
+	res, err := version.Resolve("")
+	if err != nil {
+		return err
+	}
+
+	ver := res.Version
+	if ver == "" {
+		ver, err = install(res)
+		if err != nil {
+			return err
+		}
+	}
+
+	paths, err := config.GetPaths()
+	if err != nil {
+		return err
+	}
+
+	binDir := paths.VersionBinPath(ver)
+	binary := filepath.Join(binDir, name)
+	if _, err := os.Stat(binary); err != nil {
+		return fmt.Errorf("%s is not part of Go %s", name, ver)
+	}
+
+	// Put the real toolchain first so nested go invocations skip the shim,
+	// and apply the variables configured for the version
+	vars, err := shell.VersionEnv(ver)
+	if err != nil {
+		return err
+	}
+
+	return syscall.Exec(binary, append([]string{name}, args...), vars.Apply(os.Environ()))
+}
+
+// install installs the version a resolution asks for, if auto_install allows it
+func install(res *version.Resolution) (string, error) {
+	if !version.AutoInstallAllowed() {
+		return "", fmt.Errorf("Go %s (from %s) is not installed, run 'govm install %s'", res.Raw, res.Origin, res.Raw)
+	}
+
+	mgr, err := version.NewManager()
+	if err != nil {
+		return "", err
+	}
+
+	// The tool's stdout may be consumed by a script, keep install output off it
+	defer ui.SetOutput(ui.SetOutput(os.Stderr))
+
+	return mgr.EnsureInstalled(res.Raw)
+}
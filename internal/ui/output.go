@@ -2,29 +2,41 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
 
-// Print prints a message to stdout
+// out receives messages, prompts and tables. Errors always go to stderr.
+var out io.Writer = os.Stdout
+
+// SetOutput redirects messages, e.g. to stderr when stdout is evaluated by
+// a shell or consumed by a script, and returns the previous writer
+func SetOutput(w io.Writer) io.Writer {
+	prev := out
+	out = w
+	return prev
+}
+
+// Print prints a message
 func Print(format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	fmt.Fprintf(out, format, args...)
 }
 
-// Println prints a message to stdout with newline
+// Println prints a message with newline
 func Println(args ...interface{}) {
-	fmt.Println(args...)
+	fmt.Fprintln(out, args...)
 }
 
-// Printf prints a formatted message to stdout
+// Printf prints a formatted message
 func Printf(format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	fmt.Fprintf(out, format, args...)
 }
 
 // PrintSuccess prints a success message
 func PrintSuccess(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Success.Sprint(SymbolSuccess), msg)
+	fmt.Fprintf(out, "%s %s\n", Success.Sprint(SymbolSuccess), msg)
 }
 
 // PrintError prints an error message
@@ -36,19 +48,19 @@ func PrintError(format string, args ...interface{}) {
 // PrintWarning prints a warning message
 func PrintWarning(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Warning.Sprint(SymbolWarning), msg)
+	fmt.Fprintf(out, "%s %s\n", Warning.Sprint(SymbolWarning), msg)
 }
 
 // PrintInfo prints an info message
 func PrintInfo(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Info.Sprint(SymbolInfo), msg)
+	fmt.Fprintf(out, "%s %s\n", Info.Sprint(SymbolInfo), msg)
 }
 
 // PrintHint prints a hint message (dimmed)
 func PrintHint(format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("  %s %s\n", Hint.Sprint(SymbolArrow), Hint.Sprint(msg))
+	fmt.Fprintf(out, "  %s %s\n", Hint.Sprint(SymbolArrow), Hint.Sprint(msg))
 }
 
 // PrintVersion prints a version with styling
@@ -68,33 +80,33 @@ func PrintVersion(ver string, current bool, installed bool) {
 		ver = White.Sprint(ver)
 	}
 
-	fmt.Printf("%s%s%s\n", prefix, ver, suffix)
+	fmt.Fprintf(out, "%s%s%s\n", prefix, ver, suffix)
 }
 
 // PrintHeader prints a section header
 func PrintHeader(title string) {
-	fmt.Printf("\n%s\n", Bold.Sprint(title))
-	fmt.Println(Dim.Sprint(strings.Repeat(SymbolHorizontal, len(title)+2)))
+	fmt.Fprintf(out, "\n%s\n", Bold.Sprint(title))
+	fmt.Fprintln(out, Dim.Sprint(strings.Repeat(SymbolHorizontal, len(title)+2)))
 }
 
 // PrintKeyValue prints a key-value pair
 func PrintKeyValue(key, value string) {
-	fmt.Printf("  %s: %s\n", Dim.Sprint(key), value)
+	fmt.Fprintf(out, "  %s: %s\n", Dim.Sprint(key), value)
 }
 
 // PrintBullet prints a bullet point
 func PrintBullet(text string) {
-	fmt.Printf("  %s %s\n", Cyan.Sprint(SymbolBullet), text)
+	fmt.Fprintf(out, "  %s %s\n", Cyan.Sprint(SymbolBullet), text)
 }
 
 // PrintCommand prints a command example
 func PrintCommand(cmd string) {
-	fmt.Printf("  %s %s\n", Dim.Sprint("$"), Command.Sprint(cmd))
+	fmt.Fprintf(out, "  %s %s\n", Dim.Sprint("$"), Command.Sprint(cmd))
 }
 
 // Confirm asks for user confirmation
 func Confirm(prompt string) bool {
-	fmt.Printf("%s [y/N]: ", prompt)
+	fmt.Fprintf(out, "%s [y/N]: ", prompt)
 	var response string
 	fmt.Scanln(&response)
 	response = strings.ToLower(strings.TrimSpace(response))
@@ -111,23 +123,23 @@ func PrintLogo() {
    __/ |
   |___/   Go Version Manager
 `
-	fmt.Println(Cyan.Sprint(logo))
+	fmt.Fprintln(out, Cyan.Sprint(logo))
 }
 
 // PrintVersionInfo prints version information
 func PrintVersionInfo(version, buildTime string) {
-	fmt.Printf("%s %s\n", Dim.Sprint("Version:"), Version.Sprint(version))
+	fmt.Fprintf(out, "%s %s\n", Dim.Sprint("Version:"), Version.Sprint(version))
 	if buildTime != "unknown" {
-		fmt.Printf("%s %s\n", Dim.Sprint("Built:"), buildTime)
+		fmt.Fprintf(out, "%s %s\n", Dim.Sprint("Built:"), buildTime)
 	}
 }
 
 // ClearLine clears the current line
 func ClearLine() {
-	fmt.Print("\r\033[K")
+	fmt.Fprint(out, "\r\033[K")
 }
 
 // MoveCursorUp moves cursor up n lines
 func MoveCursorUp(n int) {
-	fmt.Printf("\033[%dA", n)
+	fmt.Fprintf(out, "\033[%dA", n)
 }
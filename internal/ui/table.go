@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // Table represents a simple text table
@@ -16,7 +17,7 @@ type Table struct {
 func NewTable(headers ...string) *Table {
 	widths := make([]int, len(headers))
 	for i, h := range headers {
-		widths[i] = len(h)
+		widths[i] = utf8.RuneCountInString(h)
 	}
 	return &Table{
 		headers: headers,
@@ -34,30 +35,30 @@ func (t *Table) AddRow(values ...string) {
 
 	// Update widths
 	for i, v := range values {
-		if i < len(t.widths) && len(v) > t.widths[i] {
-			t.widths[i] = len(v)
+		if i < len(t.widths) && utf8.RuneCountInString(v) > t.widths[i] {
+			t.widths[i] = utf8.RuneCountInString(v)
 		}
 	}
 
 	t.rows = append(t.rows, values)
 }
 
-// Render renders the table to stdout
+// Render renders the table to the message output
 func (t *Table) Render() {
 	// Print headers
 	headerLine := t.formatRow(t.headers, true)
-	fmt.Println(headerLine)
+	fmt.Fprintln(out, headerLine)
 
 	// Print separator
 	sep := make([]string, len(t.headers))
 	for i, w := range t.widths {
 		sep[i] = strings.Repeat(SymbolHorizontal, w+2)
 	}
-	fmt.Println(Dim.Sprint(strings.Join(sep, "")))
+	fmt.Fprintln(out, Dim.Sprint(strings.Join(sep, "")))
 
 	// Print rows
 	for _, row := range t.rows {
-		fmt.Println(t.formatRow(row, false))
+		fmt.Fprintln(out, t.formatRow(row, false))
 	}
 }
 
@@ -80,11 +81,11 @@ func (t *Table) formatRow(values []string, isHeader bool) string {
 func (t *Table) RenderCompact() {
 	for _, row := range t.rows {
 		if len(row) > 0 {
-			fmt.Printf("  %s", row[0])
+			fmt.Fprintf(out, "  %s", row[0])
 			if len(row) > 1 && row[1] != "" {
-				fmt.Printf("  %s", Dim.Sprint(row[1]))
+				fmt.Fprintf(out, "  %s", Dim.Sprint(row[1]))
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 	}
 }
@@ -0,0 +1,58 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// searchDirs returns dir followed by the parent directories a version search
+// visits, nearest first. The walk ends at the first boundary:
+//
+//   - a repository root (a directory with a .git entry) when inherit_stop
+//     has "git"; the root itself is searched
+//   - $HOME when inherit_stop has "home"; it is only searched as dir itself
+//   - a ceiling_directories entry, likewise only searched as dir itself
+//   - inherit_max_depth parent directories
+//
+// The bash and zsh hooks apply the same rules in _govm_next_search_dir.
+func searchDirs(dir string, cfg *config.Config) []string {
+	home := ""
+	if cfg.Stops(config.StopHome) {
+		if h, err := os.UserHomeDir(); err == nil {
+			home = filepath.Clean(h)
+		}
+	}
+	ceilings := cfg.CeilingDirs()
+
+	dirs := []string{dir}
+	for depth := 0; ; depth++ {
+		if cfg.Stops(config.StopGit) {
+			if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+				break
+			}
+		}
+		if cfg.InheritDepth > 0 && depth >= cfg.InheritDepth {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir || parent == home || isCeiling(parent, ceilings) {
+			break
+		}
+		dirs = append(dirs, parent)
+		dir = parent
+	}
+	return dirs
+}
+
+// isCeiling reports whether dir is one of the ceiling directories
+func isCeiling(dir string, ceilings []string) bool {
+	for _, c := range ceilings {
+		if dir == c {
+			return true
+		}
+	}
+	return false
+}
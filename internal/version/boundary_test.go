@@ -0,0 +1,76 @@
+package version
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+func TestSearchDirs(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"home/user/repo/.git/HEAD":      "ref: refs/heads/main\n",
+		"home/user/repo/a/b/go.mod":     "module b\n",
+		"home/user/worktree/.git":       "gitdir: ../repo/.git\n",
+		"home/user/worktree/c/go.mod":   "module c\n",
+		"home/user/proj/x/y/z/.keep":    "",
+		"home/user/proj/x/y/z/w/.keep":  "",
+		"home/other/deep/project/.keep": "",
+	})
+	t.Setenv("HOME", filepath.Join(root, "home", "user"))
+	rel := func(dirs []string) []string {
+		var out []string
+		for _, d := range dirs {
+			r, err := filepath.Rel(root, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, filepath.ToSlash(r))
+		}
+		return out
+	}
+
+	both := []string{config.StopGit, config.StopHome}
+	tests := []struct {
+		name     string
+		dir      string
+		stop     []string
+		depth    int
+		ceilings []string // Relative to root; root itself is always a ceiling
+		want     []string
+	}{
+		{"repository root is searched last", "home/user/repo/a/b", both, 0, nil,
+			[]string{"home/user/repo/a/b", "home/user/repo/a", "home/user/repo"}},
+		{".git file of a worktree", "home/user/worktree/c", both, 0, nil,
+			[]string{"home/user/worktree/c", "home/user/worktree"}},
+		{"home is not entered", "home/user/proj/x", both, 0, nil,
+			[]string{"home/user/proj/x", "home/user/proj"}},
+		{"home is searched as dir itself", "home/user", both, 0, nil,
+			[]string{"home/user", "home"}},
+		{"without stops", "home/user/repo/a", nil, 0, nil,
+			[]string{"home/user/repo/a", "home/user/repo", "home/user", "home"}},
+		{"ceiling", "home/user/proj/x/y/z", nil, 0, []string{"home/user/proj"},
+			[]string{"home/user/proj/x/y/z", "home/user/proj/x/y", "home/user/proj/x"}},
+		{"ceiling is searched as dir itself", "home/user/proj", nil, 0, []string{"home/user/proj"},
+			[]string{"home/user/proj", "home/user", "home"}},
+		{"max depth", "home/user/proj/x/y/z/w", both, 2, nil,
+			[]string{"home/user/proj/x/y/z/w", "home/user/proj/x/y/z", "home/user/proj/x/y"}},
+		{"max depth beyond the boundary", "home/user/repo/a/b", both, 5, nil,
+			[]string{"home/user/repo/a/b", "home/user/repo/a", "home/user/repo"}},
+		{"outside home", "home/other/deep/project", both, 0, nil,
+			[]string{"home/other/deep/project", "home/other/deep", "home/other", "home"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{InheritStop: tt.stop, InheritDepth: tt.depth, Ceilings: []string{root}}
+			for _, c := range tt.ceilings {
+				cfg.Ceilings = append(cfg.Ceilings, filepath.Join(root, filepath.FromSlash(c)))
+			}
+			got := rel(searchDirs(filepath.Join(root, filepath.FromSlash(tt.dir)), cfg))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("searchDirs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package version
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Bump is a planned rewrite of the go and toolchain directives of a go.mod
+// or go.work file
+type Bump struct {
+	Path   string
+	Before GoDirectives
+	After  GoDirectives
+	Old    []byte // File contents before
+	New    []byte // File contents after, comments and layout kept
+}
+
+// Changed reports whether the rewrite changes the file
+func (b *Bump) Changed() bool {
+	return !bytes.Equal(b.Old, b.New)
+}
+
+// Downgrade reports whether the rewrite lowers the go directive or the
+// toolchain the file asks for
+func (b *Bump) Downgrade() bool {
+	return compareVersions(b.After.Go, b.Before.Go) < 0 ||
+		compareVersions(b.After.Version(), b.Before.Version()) < 0
+}
+
+// directiveEditor is implemented by modfile.File and modfile.WorkFile
+type directiveEditor interface {
+	AddGoStmt(version string) error
+	AddToolchainStmt(name string) error
+	DropToolchainStmt()
+	Cleanup()
+}
+
+// PlanBump computes the rewrite of a go.mod or go.work file that sets the go
+// directive to goVersion ("" keeps it) and asks for toolchain, a full
+// version. The toolchain line is dropped when it would not be newer than
+// the go line, as 'go mod tidy' does. The file is not written.
+func PlanBump(path, goVersion, toolchain string) (*Bump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Bump{Path: path, Old: data}
+
+	var editor directiveEditor
+	var syntax *modfile.FileSyntax
+	if filepath.Base(path) == GoWorkFile {
+		f, err := modfile.ParseWork(path, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if f.Go != nil {
+			b.Before.Go = f.Go.Version
+		}
+		if f.Toolchain != nil {
+			b.Before.Toolchain = strings.TrimPrefix(f.Toolchain.Name, "go")
+		}
+		editor, syntax = f, f.Syntax
+	} else {
+		f, err := modfile.Parse(path, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if f.Go != nil {
+			b.Before.Go = f.Go.Version
+		}
+		if f.Toolchain != nil {
+			b.Before.Toolchain = strings.TrimPrefix(f.Toolchain.Name, "go")
+		}
+		editor, syntax = f, f.Syntax
+	}
+
+	b.After.Go = b.Before.Go
+	if goVersion != "" {
+		if err := editor.AddGoStmt(goVersion); err != nil {
+			return nil, err
+		}
+		b.After.Go = goVersion
+	} else if compareVersions(toolchain, b.Before.Go) < 0 {
+		return nil, fmt.Errorf("toolchain go%s would be older than the go %s line of %s", toolchain, b.Before.Go, path)
+	}
+
+	if compareVersions(toolchain, b.After.Go) > 0 {
+		if err := editor.AddToolchainStmt("go" + toolchain); err != nil {
+			return nil, err
+		}
+		b.After.Toolchain = toolchain
+	} else {
+		editor.DropToolchainStmt()
+	}
+
+	editor.Cleanup()
+	b.New = modfile.Format(syntax)
+	return b, nil
+}
+
+// WorkspaceModules returns the module directories in the use directives of
+// a go.work file
+func WorkspaceModules(path string) ([]string, error) {
+	return parseWorkUses(path)
+}
@@ -0,0 +1,130 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModFile writes a go.mod or go.work file into a temporary directory
+func writeModFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPlanBump(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		goVersion string
+		toolchain string
+		want      string // New file contents
+		downgrade bool
+	}{
+		{
+			name:      "raise go and add toolchain",
+			file:      GoModFile,
+			content:   "module example.com/m\n\ngo 1.21\n",
+			goVersion: "1.23",
+			toolchain: "1.23.4",
+			want:      "module example.com/m\n\ngo 1.23\n\ntoolchain go1.23.4\n",
+		},
+		{
+			name:      "toolchain not newer than go is dropped",
+			file:      GoModFile,
+			content:   "module example.com/m\n\ngo 1.21\n\ntoolchain go1.21.5\n",
+			goVersion: "1.23.4",
+			toolchain: "1.23.4",
+			want:      "module example.com/m\n\ngo 1.23.4\n",
+		},
+		{
+			name:      "comments are kept",
+			file:      GoModFile,
+			content:   "// The module\nmodule example.com/m\n\n// Minimum for users\ngo 1.21 // keep\n",
+			goVersion: "1.22",
+			toolchain: "1.22.1",
+			want:      "// The module\nmodule example.com/m\n\n// Minimum for users\ngo 1.22 // keep\n\ntoolchain go1.22.1\n",
+		},
+		{
+			name:      "toolchain only keeps the go line",
+			file:      GoModFile,
+			content:   "module example.com/m\n\ngo 1.21\n\ntoolchain go1.21.5\n",
+			toolchain: "1.23.4",
+			want:      "module example.com/m\n\ngo 1.21\n\ntoolchain go1.23.4\n",
+		},
+		{
+			name:      "lowering go is a downgrade",
+			file:      GoModFile,
+			content:   "module example.com/m\n\ngo 1.22.3\n",
+			goVersion: "1.21",
+			toolchain: "1.21.13",
+			want:      "module example.com/m\n\ngo 1.21\n\ntoolchain go1.21.13\n",
+			downgrade: true,
+		},
+		{
+			name:      "lowering the toolchain is a downgrade",
+			file:      GoModFile,
+			content:   "module example.com/m\n\ngo 1.21\n\ntoolchain go1.23.4\n",
+			toolchain: "1.22.0",
+			want:      "module example.com/m\n\ngo 1.21\n\ntoolchain go1.22.0\n",
+			downgrade: true,
+		},
+		{
+			name:      "workspace",
+			file:      GoWorkFile,
+			content:   "go 1.21\n\nuse ./a\n",
+			goVersion: "1.22",
+			toolchain: "1.22.5",
+			want:      "go 1.22\n\ntoolchain go1.22.5\n\nuse ./a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeModFile(t, tt.file, tt.content)
+			b, err := PlanBump(path, tt.goVersion, tt.toolchain)
+			if err != nil {
+				t.Fatalf("PlanBump: %v", err)
+			}
+			if got := string(b.New); got != tt.want {
+				t.Errorf("new contents:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if got := b.Downgrade(); got != tt.downgrade {
+				t.Errorf("Downgrade() = %v, want %v", got, tt.downgrade)
+			}
+			if !b.Changed() {
+				t.Error("Changed() = false, want true")
+			}
+
+			// Planning never writes the file
+			if data, _ := os.ReadFile(path); string(data) != tt.content {
+				t.Error("PlanBump modified the file")
+			}
+		})
+	}
+}
+
+func TestPlanBumpUnchanged(t *testing.T) {
+	content := "module example.com/m\n\ngo 1.23\n\ntoolchain go1.23.4\n"
+	b, err := PlanBump(writeModFile(t, GoModFile, content), "1.23", "1.23.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Changed() || b.Downgrade() {
+		t.Errorf("Changed() = %v, Downgrade() = %v, want both false", b.Changed(), b.Downgrade())
+	}
+}
+
+func TestPlanBumpToolchainOlderThanGo(t *testing.T) {
+	path := writeModFile(t, GoModFile, "module example.com/m\n\ngo 1.23.0\n")
+	_, err := PlanBump(path, "", "1.22.5")
+	if err == nil || !strings.Contains(err.Error(), "older than the go 1.23.0 line") {
+		t.Errorf("PlanBump error = %v, want toolchain older than go line", err)
+	}
+}
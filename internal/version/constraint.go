@@ -0,0 +1,218 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+)
+
+// IsConstraint reports whether spec is a version constraint or pattern
+// (e.g. "<1.21", "~1.21", "^1.22", ">=1.21 <1.23", "1.22.x") rather than
+// a plain or partial version
+func IsConstraint(spec string) bool {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return false
+	}
+	if strings.ContainsAny(spec, "<>=!~^,* ") {
+		return true
+	}
+	return strings.HasSuffix(spec, ".x") || strings.HasSuffix(spec, ".X")
+}
+
+// Constraint is a parsed version constraint expression. Terms separated
+// by commas or whitespace must all match. Supported terms:
+//
+//	1.22.5, =1.22.5      exact version
+//	1.22, 1.22.x, 1.22.* any 1.22 release
+//	<, <=, >, >=, !=     comparisons
+//	~1.21                >=1.21.0 <1.22.0 (patch releases of a minor line)
+//	^1.22                >=1.22.0 <2.0.0 (compatible releases)
+//
+// Pre-releases (rc, beta) only match when the expression names one.
+type Constraint struct {
+	raw        string
+	terms      []func(*goversion.Version) bool
+	prerelease bool
+}
+
+// ParseConstraint parses a constraint expression
+func ParseConstraint(spec string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(spec)}
+	c.prerelease = isPrerelease(c.raw)
+
+	fields := strings.FieldsFunc(c.raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty constraint")
+	}
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		// Allow whitespace between operator and version: ">= 1.21"
+		if strings.Trim(field, "<>=!~^") == "" && i+1 < len(fields) {
+			i++
+			field += fields[i]
+		}
+
+		term, err := parseTerm(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", c.raw, err)
+		}
+		c.terms = append(c.terms, term)
+	}
+
+	return c, nil
+}
+
+// Check reports whether version satisfies the constraint
+func (c *Constraint) Check(version string) bool {
+	v, err := goversion.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	if v.Prerelease() != "" && !c.prerelease {
+		return false
+	}
+	for _, term := range c.terms {
+		if !term(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the expression as written
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// parseTerm parses a single constraint term into a predicate
+func parseTerm(term string) (func(*goversion.Version) bool, error) {
+	op := strings.TrimRight(term[:len(term)-len(strings.TrimLeft(term, "<>=!~^"))], " ")
+	value := strings.TrimPrefix(strings.TrimLeft(term, "<>=!~^"), "go")
+
+	// Wildcards and bare partial versions match a whole release line
+	if op == "" || op == "=" || op == "==" {
+		for _, suffix := range []string{".x", ".X", ".*"} {
+			if strings.HasSuffix(value, suffix) {
+				prefix := strings.TrimSuffix(value, suffix)
+				return func(v *goversion.Version) bool { return hasSegmentPrefix(v, prefix) }, nil
+			}
+		}
+		if op == "" && len(strings.Split(value, ".")) < 3 && !isPrerelease(value) {
+			return func(v *goversion.Version) bool { return hasSegmentPrefix(v, value) }, nil
+		}
+	}
+
+	bound, err := goversion.NewVersion(value)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "", "=", "==":
+		return func(v *goversion.Version) bool { return v.Equal(bound) }, nil
+	case "!=":
+		return func(v *goversion.Version) bool { return !v.Equal(bound) }, nil
+	case "<":
+		return func(v *goversion.Version) bool { return v.LessThan(bound) }, nil
+	case "<=":
+		return func(v *goversion.Version) bool { return v.LessThanOrEqual(bound) }, nil
+	case ">":
+		return func(v *goversion.Version) bool { return v.GreaterThan(bound) }, nil
+	case ">=":
+		return func(v *goversion.Version) bool { return v.GreaterThanOrEqual(bound) }, nil
+	case "~", "~>":
+		upper := nextRelease(bound, strings.Count(value, ".") >= 1)
+		return func(v *goversion.Version) bool {
+			return v.GreaterThanOrEqual(bound) && v.LessThan(upper)
+		}, nil
+	case "^":
+		upper := nextRelease(bound, false)
+		return func(v *goversion.Version) bool {
+			return v.GreaterThanOrEqual(bound) && v.LessThan(upper)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown operator %q", op)
+	}
+}
+
+// nextRelease returns the next minor release after v (e.g. 1.21.5 -> 1.22.0)
+// or, if minor is false, the next major release (1.21.5 -> 2.0.0)
+func nextRelease(v *goversion.Version, minor bool) *goversion.Version {
+	segments := v.Segments()
+	var next string
+	if minor {
+		next = fmt.Sprintf("%d.%d.0", segments[0], segments[1]+1)
+	} else {
+		next = fmt.Sprintf("%d.0.0", segments[0]+1)
+	}
+	upper, _ := goversion.NewVersion(next)
+	return upper
+}
+
+// hasSegmentPrefix reports whether the numeric segments of v start with
+// those of prefix ("1.22" matches 1.22.0 and 1.22.7 but not 1.2.0)
+func hasSegmentPrefix(v *goversion.Version, prefix string) bool {
+	segments := v.Segments()
+	for i, part := range strings.Split(prefix, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || i >= len(segments) || segments[i] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchVersions returns the versions from candidates that satisfy spec,
+// newest first. spec may be an exact version ("1.20.14"), a partial version
+// ("1.20", matching 1.20 itself and every 1.20.x), a wildcard ("1.19.*",
+// "1.19.x") or a constraint expression ("<1.21").
+func MatchVersions(spec string, candidates []string) ([]string, error) {
+	spec = strings.TrimSpace(spec)
+
+	var matched []string
+	switch {
+	case !IsConstraint(spec) && len(strings.Split(spec, ".")) >= 3:
+		for _, v := range candidates {
+			if v == spec {
+				matched = append(matched, v)
+			}
+		}
+	case !IsConstraint(spec):
+		matched = matchPrefix(spec, candidates)
+	default:
+		constraint, err := ParseConstraint(spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range candidates {
+			if constraint.Check(v) {
+				matched = append(matched, v)
+			}
+		}
+	}
+
+	sortVersionsDesc(matched)
+	return matched, nil
+}
+
+// matchPrefix returns the candidates equal to prefix or starting with "prefix."
+func matchPrefix(prefix string, candidates []string) []string {
+	var matched []string
+	for _, v := range candidates {
+		if v == prefix || strings.HasPrefix(v, prefix+".") {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+// isPrerelease reports whether a version or expression names a pre-release
+func isPrerelease(s string) bool {
+	return strings.Contains(s, "rc") || strings.Contains(s, "beta")
+}
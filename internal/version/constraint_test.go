@@ -0,0 +1,134 @@
+package version
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsConstraint(t *testing.T) {
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"1.22.5", false},
+		{"1.22", false},
+		{"1.23rc1", false},
+		{"", false},
+		{"<1.21", true},
+		{"~1.21", true},
+		{"^1.22", true},
+		{">=1.21 <1.23", true},
+		{"1.22.x", true},
+		{"1.19.*", true},
+		{"!=1.22.0", true},
+	}
+	for _, tt := range tests {
+		if got := IsConstraint(tt.spec); got != tt.want {
+			t.Errorf("IsConstraint(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		spec    string
+		match   []string
+		noMatch []string
+	}{
+		{"1.22.5", []string{"1.22.5"}, []string{"1.22.4", "1.22.50"}},
+		{"=1.22.5", []string{"1.22.5"}, []string{"1.22.6"}},
+		{"1.22", []string{"1.22.0", "1.22.7"}, []string{"1.2.0", "1.23.0", "1.22rc1"}},
+		{"1.22.x", []string{"1.22.0", "1.22.9"}, []string{"1.21.9", "1.23.0"}},
+		{"1.19.*", []string{"1.19.13"}, []string{"1.20.0"}},
+		{"<1.21", []string{"1.20.14", "1.19.0"}, []string{"1.21.0", "1.21rc2"}},
+		{"<=1.21.3", []string{"1.21.3", "1.21.0"}, []string{"1.21.4"}},
+		{">1.21", []string{"1.21.1", "1.22.0"}, []string{"1.21.0"}},
+		{">= 1.21", []string{"1.21.0", "1.23.4"}, []string{"1.20.14"}},
+		{"!=1.22.0", []string{"1.22.1"}, []string{"1.22.0"}},
+		{"~1.21", []string{"1.21.0", "1.21.13"}, []string{"1.22.0", "1.20.9"}},
+		{"~1.21.4", []string{"1.21.4", "1.21.9"}, []string{"1.21.3", "1.22.0"}},
+		{"^1.22", []string{"1.22.0", "1.30.1"}, []string{"1.21.9", "2.0.0"}},
+		{">=1.21 <1.23", []string{"1.21.0", "1.22.9"}, []string{"1.23.0", "1.20.1"}},
+		{">=1.21, <1.23", []string{"1.22.0"}, []string{"1.23.0"}},
+		{">=1.23rc1", []string{"1.23rc2", "1.23.0"}, []string{"1.22.9"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.spec)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", tt.spec, err)
+			continue
+		}
+		for _, v := range tt.match {
+			if !c.Check(v) {
+				t.Errorf("%q should match %s", tt.spec, v)
+			}
+		}
+		for _, v := range tt.noMatch {
+			if c.Check(v) {
+				t.Errorf("%q should not match %s", tt.spec, v)
+			}
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, spec := range []string{"", " , ", "<abc", ">=", "~x.y"} {
+		if _, err := ParseConstraint(spec); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestMatchVersions(t *testing.T) {
+	installed := []string{"1.19.13", "1.20", "1.20.14", "1.20.2", "1.21.0", "1.21rc2", "1.2.1"}
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		// A partial version also matches a directory of that exact name,
+		// but the newest patch comes first
+		{"1.20", []string{"1.20.14", "1.20.2", "1.20"}},
+		{"1.20.2", []string{"1.20.2"}},
+		{"1.20.3", nil},
+		{"1.2", []string{"1.2.1"}},
+		{"1.21", []string{"1.21.0"}},
+		{"1.21rc2", []string{"1.21rc2"}},
+		{"<1.21", []string{"1.20.14", "1.20.2", "1.20", "1.19.13", "1.2.1"}},
+		{"~1.20", []string{"1.20.14", "1.20.2", "1.20"}},
+		{"1.19.x", []string{"1.19.13"}},
+		{">=1.22", nil},
+	}
+	for _, tt := range tests {
+		got, err := MatchVersions(tt.spec, installed)
+		if err != nil {
+			t.Errorf("MatchVersions(%q): %v", tt.spec, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("MatchVersions(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	if _, err := MatchVersions("<abc", installed); err == nil {
+		t.Error("MatchVersions with an invalid constraint succeeded")
+	}
+}
+
+func TestSelectInstalled(t *testing.T) {
+	installed := []string{"1.20", "1.20.14", "1.21.2"}
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"1.20", "1.20.14"},
+		{"go1.20", "1.20.14"},
+		{"1.21", "1.21.2"},
+		{"~1.21", "1.21.2"},
+		{"1.22", ""},
+	}
+	for _, tt := range tests {
+		if got := SelectInstalled(tt.spec, installed); got != tt.want {
+			t.Errorf("SelectInstalled(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -7,16 +7,49 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/wenzzy/govm/internal/config"
+	"golang.org/x/mod/modfile"
 )
 
 var (
 	// goVersionRegex matches "go X.Y" or "go X.Y.Z" in go.mod/go.work files
-	goVersionRegex = regexp.MustCompile(`^go\s+(\d+\.\d+(?:\.\d+)?)`)
+	goVersionRegex = regexp.MustCompile(`^go\s+(\d+\.\d+(?:\.\d+)?(?:rc\d+|beta\d+)?)`)
+	// toolchainRegex matches "toolchain goX.Y.Z" in go.mod/go.work files
+	toolchainRegex = regexp.MustCompile(`^toolchain\s+go(\d+\.\d+(?:\.\d+)?(?:rc\d+|beta\d+)?)`)
+)
+
+// Version files checked in every directory, in precedence order.
+// An explicit pin (.go-version, then .tool-versions) beats the module files,
+// and go.work beats go.mod.
+const (
+	GoVersionFile   = ".go-version"
+	ToolVersionFile = ".tool-versions"
+	GoWorkFile      = "go.work"
+	GoModFile       = "go.mod"
 )
 
-// DetectVersion detects the Go version from go.mod or go.work in the given directory
-// It searches from the given directory up to the root
+// versionFiles lists the version sources in precedence order with their parsers
+var versionFiles = []struct {
+	name  string
+	parse func(path string) (string, error)
+}{
+	{GoVersionFile, parseGoVersionPin},
+	{ToolVersionFile, parseToolVersions},
+	{GoWorkFile, parseGoWorkVersion},
+	{GoModFile, parseGoVersionFile},
+}
+
+// DetectVersion detects the Go version from .go-version, .tool-versions,
+// go.work or go.mod in the given directory
+// It searches from the given directory up to the first search boundary
+// (see searchDirs), then applies GOTOOLCHAIN
 func DetectVersion(dir string) (string, string, error) {
+	return applyGoToolchainEnv(detectUp(dir))
+}
+
+// detectUp searches dir and its parents for the first version file
+func detectUp(dir string) (string, string, error) {
 	if dir == "" {
 		var err error
 		dir, err = os.Getwd()
@@ -31,34 +64,25 @@ func DetectVersion(dir string) (string, string, error) {
 		return "", "", err
 	}
 
-	// Search up the directory tree
-	for {
-		// Check for go.work first (takes precedence)
-		goWorkPath := filepath.Join(dir, "go.work")
-		if version, err := parseGoVersionFile(goWorkPath); err == nil && version != "" {
-			return version, goWorkPath, nil
+	// Search up the directory tree, up to the first boundary
+	for _, d := range searchDirs(dir, config.Get()) {
+		if version, source, ok := detectInDir(d); ok {
+			return version, source, nil
 		}
-
-		// Then check for go.mod
-		goModPath := filepath.Join(dir, "go.mod")
-		if version, err := parseGoVersionFile(goModPath); err == nil && version != "" {
-			return version, goModPath, nil
-		}
-
-		// Move up one directory
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached root
-			break
-		}
-		dir = parent
 	}
 
-	return "", "", fmt.Errorf("no go.mod or go.work found")
+	return "", "", fmt.Errorf("no .go-version, .tool-versions, go.work or go.mod found")
 }
 
-// DetectVersionInDir detects version only in the specific directory (no parent search)
+// DetectVersionInDir detects version only in the specific directory (no parent search),
+// then applies GOTOOLCHAIN
 func DetectVersionInDir(dir string) (string, string, error) {
+	return applyGoToolchainEnv(DeclaredVersionInDir(dir))
+}
+
+// DeclaredVersionInDir returns the version the files in a single directory
+// declare, ignoring GOTOOLCHAIN
+func DeclaredVersionInDir(dir string) (string, string, error) {
 	if dir == "" {
 		var err error
 		dir, err = os.Getwd()
@@ -67,31 +91,145 @@ func DetectVersionInDir(dir string) (string, string, error) {
 		}
 	}
 
-	// Check for go.work first
-	goWorkPath := filepath.Join(dir, "go.work")
-	if version, err := parseGoVersionFile(goWorkPath); err == nil && version != "" {
-		return version, goWorkPath, nil
+	if version, source, ok := detectInDir(dir); ok {
+		return version, source, nil
 	}
 
-	// Then check for go.mod
-	goModPath := filepath.Join(dir, "go.mod")
-	if version, err := parseGoVersionFile(goModPath); err == nil && version != "" {
-		return version, goModPath, nil
+	return "", "", fmt.Errorf("no .go-version, .tool-versions, go.work or go.mod found in %s", dir)
+}
+
+// detectInDir returns the version from the first version file in dir that declares one
+func detectInDir(dir string) (string, string, bool) {
+	for _, vf := range versionFiles {
+		path := filepath.Join(dir, vf.name)
+		if version, err := vf.parse(path); err == nil && version != "" {
+			return version, path, true
+		}
+	}
+	return "", "", false
+}
+
+// GoDirectives holds the version directives of a go.mod or go.work file
+type GoDirectives struct {
+	Go            string // Version from the "go" line
+	Toolchain     string // Version from the "toolchain" line, without the "go" prefix
+	GoLine        int    // 1-based line of the go directive, 0 if parsed from elsewhere
+	ToolchainLine int    // 1-based line of the toolchain directive, likewise
+}
+
+// Version returns the toolchain the file asks for: the toolchain directive
+// when it is newer than the go directive, otherwise the go directive
+func (d GoDirectives) Version() string {
+	if d.Toolchain != "" && compareVersions(d.Toolchain, d.Go) > 0 {
+		return d.Toolchain
 	}
+	return d.Go
+}
 
-	return "", "", fmt.Errorf("no go.mod or go.work found in %s", dir)
+// ParseGoDirectives parses the go and toolchain directives of a go.mod or
+// go.work file, as version detection reads them
+func ParseGoDirectives(path string) (GoDirectives, error) {
+	return parseGoDirectives(path)
 }
 
-// parseGoVersionFile parses a go.mod or go.work file and extracts the Go version
+// parseGoVersionFile parses a go.mod or go.work file and extracts the Go version,
+// preferring the toolchain directive
 func parseGoVersionFile(path string) (string, error) {
-	file, err := os.Open(path)
+	directives, err := parseGoDirectives(path)
 	if err != nil {
 		return "", err
 	}
+	return directives.Version(), nil
+}
+
+// Workspace holds the toolchain requirement of a go.work file and the
+// modules it uses
+type Workspace struct {
+	Directives GoDirectives // Directives of go.work itself
+	Version    string       // Newest version go.work or any used module asks for
+	DrivenBy   string       // go.mod that raised Version above go.work, "" if none
+}
+
+// parseGoWorkVersion parses a go.work file and returns the version the whole
+// workspace needs
+func parseGoWorkVersion(path string) (string, error) {
+	ws, err := parseWorkspace(path)
+	if err != nil {
+		return "", err
+	}
+	return ws.Version, nil
+}
+
+// parseWorkspace parses a go.work file and the go.mod of every module in its
+// use directives. The go command refuses to build a workspace with an older
+// toolchain than any of its modules require, so the workspace needs the
+// newest version among them.
+func parseWorkspace(path string) (Workspace, error) {
+	var ws Workspace
+	directives, err := parseGoDirectives(path)
+	if err == nil {
+		ws.Directives = directives
+		ws.Version = directives.Version()
+	}
+
+	uses, useErr := parseWorkUses(path)
+	if useErr != nil {
+		return ws, useErr
+	}
+	for _, dir := range uses {
+		modPath := filepath.Join(dir, GoModFile)
+		module, err := parseGoDirectives(modPath)
+		if err != nil {
+			continue
+		}
+		if v := module.Version(); compareVersions(v, ws.Version) > 0 {
+			ws.Version = v
+			ws.DrivenBy = modPath
+		}
+	}
+
+	if ws.Version == "" {
+		return ws, fmt.Errorf("no go version found in %s or its modules", path)
+	}
+	return ws, nil
+}
+
+// parseWorkUses returns the module directories of a go.work file's use
+// directives, single-line or block form, with relative paths resolved
+// against the go.work directory
+func parseWorkUses(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(path), dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// parseGoDirectives parses the go and toolchain directives of a go.mod or go.work file
+func parseGoDirectives(path string) (GoDirectives, error) {
+	var directives GoDirectives
+
+	file, err := os.Open(path)
+	if err != nil {
+		return directives, err
+	}
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	for n := 1; scanner.Scan(); n++ {
 		line := strings.TrimSpace(scanner.Text())
 
 		// Skip empty lines and comments
@@ -100,15 +238,83 @@ func parseGoVersionFile(path string) (string, error) {
 		}
 
 		// Look for "go X.Y" or "go X.Y.Z"
-		matches := goVersionRegex.FindStringSubmatch(line)
-		if len(matches) >= 2 {
-			return matches[1], nil
+		if matches := goVersionRegex.FindStringSubmatch(line); len(matches) >= 2 && directives.Go == "" {
+			directives.Go, directives.GoLine = matches[1], n
+		}
+
+		// Look for "toolchain goX.Y.Z"
+		if matches := toolchainRegex.FindStringSubmatch(line); len(matches) >= 2 && directives.Toolchain == "" {
+			directives.Toolchain, directives.ToolchainLine = matches[1], n
+		}
+	}
+
+	if directives.Go == "" && directives.Toolchain == "" {
+		return directives, fmt.Errorf("no go version found in %s", path)
+	}
+	return directives, nil
+}
+
+// parseGoVersionPin parses a .go-version file: the first non-empty,
+// non-comment line holds the version, optionally prefixed with "go"
+func parseGoVersionPin(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if version := pinnedVersion(line); version != "" {
+			return version, nil
+		}
+		break
+	}
+
+	return "", fmt.Errorf("no go version found in %s", path)
+}
+
+// parseToolVersions parses an asdf .tool-versions file and returns the
+// first version listed for the "golang" (or "go") tool
+func parseToolVersions(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "golang" && fields[0] != "go") {
+			continue
+		}
+		if version := pinnedVersion(fields[1]); version != "" {
+			return version, nil
 		}
 	}
 
 	return "", fmt.Errorf("no go version found in %s", path)
 }
 
+// pinnedVersion normalizes a pinned version value, returning "" for values
+// that are not versions (e.g. "system" or "ref:...")
+func pinnedVersion(value string) string {
+	version := strings.TrimPrefix(value, "go")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return ""
+	}
+	return version
+}
+
 // HasGoModOrWork checks if a directory has a go.mod or go.work file
 func HasGoModOrWork(dir string) bool {
 	goModPath := filepath.Join(dir, "go.mod")
@@ -0,0 +1,145 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeFiles creates files under a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, content := range files {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestParseWorkUses(t *testing.T) {
+	tests := []struct {
+		name string
+		work string
+		want []string // Relative to the go.work directory, or absolute
+	}{
+		{"single line", "go 1.22\n\nuse ./a\nuse b\n", []string{"a", "b"}},
+		{"block", "go 1.22\n\nuse (\n\t./a\n\t\"./b c\" // quoted\n\t// ./skipped\n)\n", []string{"a", "b c"}},
+		{"parent and absolute paths", "use ../shared\nuse /opt/mod\n", []string{"../shared", "/opt/mod"}},
+		{"no use directives", "go 1.22\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeFiles(t, map[string]string{"ws/go.work": tt.work})
+			got, err := parseWorkUses(filepath.Join(root, "ws", GoWorkFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want []string
+			for _, dir := range tt.want {
+				if !filepath.IsAbs(dir) {
+					dir = filepath.Join(root, "ws", dir)
+				}
+				want = append(want, dir)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("parseWorkUses = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseWorkspace(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		version  string
+		drivenBy string // go.mod that raised the version, "" if none
+	}{
+		{
+			name: "go.work decides",
+			files: map[string]string{
+				"go.work":  "go 1.22.3\n\nuse ./a\n",
+				"a/go.mod": "module a\n\ngo 1.21\n",
+			},
+			version: "1.22.3",
+		},
+		{
+			name: "module raises go.work",
+			files: map[string]string{
+				"go.work":        "go 1.21\n\nuse (\n\t./a\n\t./svc/api\n)\n",
+				"a/go.mod":       "module a\n\ngo 1.22\n",
+				"svc/api/go.mod": "module api\n\ngo 1.23.1\n",
+			},
+			version:  "1.23.1",
+			drivenBy: "svc/api/go.mod",
+		},
+		{
+			name: "newer toolchain is preferred",
+			files: map[string]string{
+				"go.work":  "go 1.22\n\ntoolchain go1.22.5\n\nuse ./a\n",
+				"a/go.mod": "module a\n\ngo 1.21\n\ntoolchain go1.23.0\n",
+			},
+			version:  "1.23.0",
+			drivenBy: "a/go.mod",
+		},
+		{
+			name: "toolchain not newer than go is ignored",
+			files: map[string]string{
+				"go.work": "go 1.22.5\n\ntoolchain go1.22.0\n",
+			},
+			version: "1.22.5",
+		},
+		{
+			name: "relative use outside the workspace",
+			files: map[string]string{
+				"ws/go.work":    "go 1.21\n\nuse ../shared\n",
+				"shared/go.mod": "module shared\n\ngo 1.22.2\n",
+			},
+			version:  "1.22.2",
+			drivenBy: "shared/go.mod",
+		},
+		{
+			name: "version only from modules",
+			files: map[string]string{
+				"go.work":  "use ./a\nuse ./missing\n",
+				"a/go.mod": "module a\n\ngo 1.21.4\n",
+			},
+			version:  "1.21.4",
+			drivenBy: "a/go.mod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeFiles(t, tt.files)
+			work := filepath.Join(root, GoWorkFile)
+			if _, ok := tt.files["ws/go.work"]; ok {
+				work = filepath.Join(root, "ws", GoWorkFile)
+			}
+			ws, err := parseWorkspace(work)
+			if err != nil {
+				t.Fatal(err)
+			}
+			drivenBy := ""
+			if tt.drivenBy != "" {
+				drivenBy = filepath.Join(root, filepath.FromSlash(tt.drivenBy))
+			}
+			if ws.Version != tt.version || ws.DrivenBy != drivenBy {
+				t.Errorf("parseWorkspace = %s raised by %q, want %s raised by %q", ws.Version, ws.DrivenBy, tt.version, drivenBy)
+			}
+		})
+	}
+}
+
+func TestParseWorkspaceWithoutVersion(t *testing.T) {
+	root := writeFiles(t, map[string]string{"go.work": "use ./a\n", "a/go.mod": "module a\n"})
+	if ws, err := parseWorkspace(filepath.Join(root, GoWorkFile)); err == nil {
+		t.Errorf("parseWorkspace = %+v, want an error without any go line", ws)
+	}
+}
@@ -0,0 +1,72 @@
+package version
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// ConfiguredEnv returns the variables from every [versions."<spec>"] section
+// of config.toml whose spec matches version. When several specs set the same
+// variable the most specific wins: an exact version beats a minor line,
+// which beats a constraint.
+func ConfiguredEnv(version string) map[string]string {
+	cfg := config.Get()
+
+	var specs []string
+	for spec, vc := range cfg.Versions {
+		if len(vc.Env) == 0 {
+			continue
+		}
+		if matched, err := MatchVersions(spec, []string{version}); err == nil && len(matched) > 0 {
+			specs = append(specs, spec)
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		si, sj := specificity(specs[i]), specificity(specs[j])
+		if si != sj {
+			return si < sj
+		}
+		return specs[i] < specs[j]
+	})
+
+	env := make(map[string]string)
+	for _, spec := range specs {
+		for key, value := range cfg.Versions[spec].Env {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// ConfiguredEnvKeys returns every variable configured for any version,
+// sorted. govm owns these variables: they are unset when the active version
+// does not configure them.
+func ConfiguredEnvKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, vc := range config.Get().Versions {
+		for key := range vc.Env {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// specificity ranks a version spec: 0 for constraints, 1 for partial
+// versions, 2 for exact versions
+func specificity(spec string) int {
+	switch {
+	case IsConstraint(spec):
+		return 0
+	case len(strings.Split(spec, ".")) < 3 && !isPrerelease(spec):
+		return 1
+	default:
+		return 2
+	}
+}
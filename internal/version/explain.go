@@ -0,0 +1,200 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// Candidate is one version source considered while resolving a directory
+type Candidate struct {
+	Source   string // One of the Source* constants
+	Origin   string // File path, environment variable or setting
+	Raw      string // Value as written, "" if the source is not set
+	Detail   string // Extra context, e.g. the go and toolchain lines of go.mod
+	Expanded string // Raw after alias expansion, "" if Raw is not an alias
+	Version  string // Installed version satisfying Raw ("" if none)
+	Selected bool   // This candidate decides the version
+}
+
+// Explain lists every version source for dir in precedence order ('govm shell'
+// session, project files, GOTOOLCHAIN, auto-switch session, default_version,
+// global symlink) and marks the one that decides the version. Resolve uses the same logic, so the selected
+// candidate is always what shims and 'govm env' pick.
+func Explain(dir string) ([]Candidate, error) {
+	installer, err := NewInstaller()
+	if err != nil {
+		return nil, err
+	}
+	installed, err := installer.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	add := func(c Candidate) {
+		if c.Raw != "" {
+			if expanded := config.ResolveVersion(c.Raw); expanded != c.Raw {
+				c.Expanded = expanded
+			}
+			c.Version = SelectInstalled(c.Raw, installed)
+		}
+		candidates = append(candidates, c)
+	}
+
+	// Session version set by 'govm shell'
+	add(Candidate{Source: SourceSession, Origin: config.EnvShellVersion, Raw: os.Getenv(config.EnvShellVersion)})
+
+	// Project files, in the same order DetectVersion checks them
+	cfg := config.Get()
+	project := len(candidates)
+	dirs := searchDirs(dir, cfg)
+	if !cfg.InheritVersion {
+		dirs = dirs[:1]
+	}
+	for _, d := range dirs {
+		found := false
+		for _, vf := range versionFiles {
+			path := filepath.Join(d, vf.name)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			c := Candidate{Source: SourceProject, Origin: path}
+			c.Raw, c.Detail = explainFile(vf.name, path, vf.parse)
+			add(c)
+			if c.Raw != "" {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	projectVersion := ""
+	for _, c := range candidates[project:] {
+		if c.Raw != "" {
+			projectVersion = c.Raw
+			break
+		}
+	}
+
+	// GOTOOLCHAIN pins the version or raises the project minimum
+	if value := os.Getenv(EnvGoToolchain); value != "" {
+		c := Candidate{Source: SourceProject, Origin: EnvGoToolchain}
+		tc := ParseGoToolchain(value)
+		switch {
+		case tc.Version == "":
+			c.Detail = fmt.Sprintf("%s: project files decide", value)
+		case !tc.Minimum:
+			c.Raw = tc.Version
+			c.Detail = fmt.Sprintf("%s: pinned", value)
+		case projectVersion == "" || compareVersions(tc.Version, projectVersion) > 0:
+			c.Raw = tc.Version
+			c.Detail = fmt.Sprintf("%s: minimum", value)
+		default:
+			c.Detail = fmt.Sprintf("%s: minimum, project requires %s", value, projectVersion)
+		}
+		add(c)
+	}
+
+	// Session version the auto-switch hook activated for the last directory.
+	// It only records what the hook did, so project files outrank it.
+	add(Candidate{Source: SourceSession, Origin: config.EnvAutoVersion, Raw: os.Getenv(config.EnvAutoVersion)})
+
+	add(Candidate{Source: SourceDefault, Origin: "default_version", Raw: cfg.DefaultVersion})
+
+	current, err := installer.GetCurrent()
+	if err != nil {
+		return nil, err
+	}
+	add(Candidate{Source: SourceGlobal, Origin: installer.paths.Current, Raw: current})
+
+	selectCandidate(candidates)
+	return candidates, nil
+}
+
+// selectCandidate marks the candidate that decides the version: the 'govm
+// shell' version, else GOTOOLCHAIN when it applies, else the first project
+// file, else the auto-switch session version, else default_version, else the
+// global symlink
+func selectCandidate(candidates []Candidate) {
+	order := []string{config.EnvShellVersion, EnvGoToolchain, SourceProject, config.EnvAutoVersion, SourceDefault, SourceGlobal}
+	for _, want := range order {
+		for i := range candidates {
+			kind := candidates[i].Source
+			switch candidates[i].Origin {
+			case EnvGoToolchain, config.EnvShellVersion, config.EnvAutoVersion:
+				kind = candidates[i].Origin
+			}
+			if kind == want && candidates[i].Raw != "" {
+				candidates[i].Selected = true
+				return
+			}
+		}
+	}
+}
+
+// explainFile parses a version file and describes how the version was chosen
+func explainFile(name, path string, parse func(string) (string, error)) (string, string) {
+	if name != GoWorkFile && name != GoModFile {
+		version, err := parse(path)
+		if err != nil {
+			return "", "no version"
+		}
+		return version, ""
+	}
+
+	if name == GoWorkFile {
+		ws, err := parseWorkspace(path)
+		if err != nil {
+			return "", "no go or toolchain line in go.work or its modules"
+		}
+		detail := directivesDetail(ws.Directives)
+		if ws.DrivenBy != "" {
+			module, err := filepath.Rel(filepath.Dir(path), ws.DrivenBy)
+			if err != nil {
+				module = ws.DrivenBy
+			}
+			if detail != "" {
+				detail += "; "
+			}
+			detail += fmt.Sprintf("raised to %s by %s", ws.Version, module)
+		}
+		return ws.Version, detail
+	}
+
+	directives, err := parseGoDirectives(path)
+	if err != nil {
+		return "", "no go or toolchain line"
+	}
+	return directives.Version(), directivesDetail(directives)
+}
+
+// directivesDetail describes the go and toolchain directives of a file and
+// which of them is used
+func directivesDetail(directives GoDirectives) string {
+	switch {
+	case directives.Go == "" && directives.Toolchain == "":
+		return ""
+	case directives.Go == "":
+		return fmt.Sprintf("toolchain go%s", directives.Toolchain)
+	case directives.Toolchain == "":
+		return fmt.Sprintf("go %s", directives.Go)
+	case directives.Version() == directives.Toolchain:
+		return fmt.Sprintf("go %s, toolchain go%s (newer, used)", directives.Go, directives.Toolchain)
+	default:
+		return fmt.Sprintf("go %s, toolchain go%s (not newer, ignored)", directives.Go, directives.Toolchain)
+	}
+}
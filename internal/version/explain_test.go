@@ -0,0 +1,64 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// testRoot points GOVM_ROOT at a temporary directory with stub installs
+func testRoot(t *testing.T, versions ...string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("GOVM_ROOT", root)
+	for _, v := range versions {
+		bin := filepath.Join(root, "versions", v, "go", "bin")
+		if err := os.MkdirAll(bin, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.Reload() })
+}
+
+func TestResolveSessionPrecedence(t *testing.T) {
+	testRoot(t, "1.21.2", "1.22.5", "1.23.1")
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, GoModFile), []byte("module m\n\ngo 1.22.5\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := t.TempDir()
+
+	tests := []struct {
+		name        string
+		dir         string
+		shell, auto string
+		want        string // Origin of the selected candidate
+	}{
+		{"govm shell outranks project files", project, "1.21.2", "", config.EnvShellVersion},
+		{"project files outrank the hook's version", project, "", "1.23.1", filepath.Join(project, GoModFile)},
+		{"hook's version outside projects", empty, "", "1.23.1", config.EnvAutoVersion},
+		{"govm shell outranks the hook's version", empty, "1.21.2", "1.23.1", config.EnvShellVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.EnvShellVersion, tt.shell)
+			t.Setenv(config.EnvAutoVersion, tt.auto)
+			t.Setenv(EnvGoToolchain, "")
+			res, err := Resolve(tt.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Origin != tt.want {
+				t.Errorf("Resolve selected %s (%s), want %s", res.Origin, res.Raw, tt.want)
+			}
+		})
+	}
+}
@@ -86,16 +86,20 @@ func (i *Installer) SetCurrent(version string) error {
 		return fmt.Errorf("version %s is not installed", version)
 	}
 
-	// Remove existing symlink
-	if err := os.Remove(i.paths.Current); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove current symlink: %w", err)
-	}
+	// Create the new symlink under a temporary name and rename it over the
+	// existing one, so there is no moment where current is missing
+	tmpLink := filepath.Join(filepath.Dir(i.paths.Current), fmt.Sprintf(".current.%d", os.Getpid()))
+	os.Remove(tmpLink)
 
-	// Create new symlink pointing to the go directory inside version
-	if err := os.Symlink(goPath, i.paths.Current); err != nil {
+	if err := os.Symlink(goPath, tmpLink); err != nil {
 		return fmt.Errorf("failed to create symlink: %w", err)
 	}
 
+	if err := os.Rename(tmpLink, i.paths.Current); err != nil {
+		os.Remove(tmpLink)
+		return fmt.Errorf("failed to replace current symlink: %w", err)
+	}
+
 	return nil
 }
 
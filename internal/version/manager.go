@@ -2,6 +2,8 @@ package version
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/wenzzy/govm/internal/config"
@@ -44,37 +46,72 @@ func NewManager() (*Manager, error) {
 	}, nil
 }
 
-// resolveFullVersion resolves a partial version like "1.26" to a full version like "1.26.2"
-// by checking locally installed versions first, then querying go.dev
+// resolveFullVersion resolves a partial version like "1.26" or a constraint
+// like "~1.26" to a full version like "1.26.2" by checking locally installed
+// versions first, then querying go.dev. Candidates are ranked by version
+// order, so 1.26.10 beats 1.26.9.
 func (m *Manager) resolveFullVersion(version string) string {
-	parts := strings.Split(version, ".")
-	if len(parts) >= 3 {
+	if !IsConstraint(version) && len(strings.Split(version, ".")) >= 3 {
 		return version // Already a full version (X.Y.Z)
 	}
 
 	// First check locally installed versions for a match
-	installed, err := m.installer.ListInstalled()
+	if installed, err := m.installer.ListInstalled(); err == nil {
+		if best := SelectInstalled(version, installed); best != "" {
+			return best
+		}
+	}
+
+	// Query go.dev for the newest matching release
+	remote, err := ListAllVersions()
 	if err == nil {
-		prefix := version + "."
-		var best string
-		for _, v := range installed {
-			if strings.HasPrefix(v, prefix) {
-				if best == "" || v > best {
-					best = v
-				}
-			}
+		if matched, err := MatchVersions(version, remote); err == nil && len(matched) > 0 {
+			return matched[0]
 		}
-		if best != "" {
-			return best
+	}
+
+	if IsConstraint(version) {
+		return version // Nothing satisfies it; callers report it as unavailable
+	}
+	// Fall back to the first release of a partial version
+	return version + ".0"
+}
+
+// ResolveRelease resolves a version, alias, partial version or constraint to
+// the newest matching release on go.dev, installed or not. When the release
+// index is unreachable the newest matching installed version is used.
+func (m *Manager) ResolveRelease(spec string) (string, error) {
+	spec = config.NormalizeVersion(config.ResolveVersion(spec))
+	if !IsConstraint(spec) && len(strings.Split(spec, ".")) >= 3 {
+		return spec, nil
+	}
+
+	remote, err := ListAllVersions()
+	if err == nil {
+		matched, err := MatchVersions(spec, remote)
+		if err != nil {
+			return "", fmt.Errorf("invalid version or constraint %q: %w", spec, err)
 		}
+		if len(matched) == 0 {
+			return "", fmt.Errorf("no Go release matches %s", spec)
+		}
+		return matched[0], nil
 	}
 
-	// Query go.dev for the latest patch version
-	full, err := NormalizeDetectedVersion(version)
-	if err != nil {
-		return version
+	matched, matchErr := m.ResolveInstalled(spec)
+	if matchErr != nil {
+		return "", matchErr
 	}
-	return full
+	if len(matched) == 0 {
+		return "", fmt.Errorf("failed to list releases matching %s: %w", spec, err)
+	}
+	return matched[0], nil
+}
+
+// AutoInstallAllowed reports whether missing versions may be installed on
+// demand: auto_install must be on and GOTOOLCHAIN must not be in path mode
+func AutoInstallAllowed() bool {
+	return config.Get().AutoInstall && !GoToolchainFromEnv().NoDownload
 }
 
 // Install downloads and installs a Go version
@@ -120,6 +157,12 @@ func (m *Manager) Install(version string, setDefault bool, showProgress bool) er
 	}
 	spinner.Success(fmt.Sprintf("Installed Go %s", version))
 
+	if err := m.Rehash(); err != nil {
+		ui.PrintWarning("Failed to update shims: %s", err)
+	}
+
+	m.installManifestTools(version)
+
 	// Set as current/default if requested or if it's the first version
 	if setDefault {
 		return m.Use(version)
@@ -135,18 +178,29 @@ func (m *Manager) Install(version string, setDefault bool, showProgress bool) er
 	return nil
 }
 
-// Uninstall removes an installed Go version
-func (m *Manager) Uninstall(version string) error {
+// Uninstall removes an installed Go version. If it is current, another
+// version that is not in batch (the versions being removed with it) is
+// activated first. Aliases and default_version still pointing at it are
+// left to the caller.
+func (m *Manager) Uninstall(version string, batch map[string]bool) error {
 	version = config.NormalizeVersion(version)
 
 	if !m.installer.IsInstalled(version) {
 		return fmt.Errorf("version %s is not installed", version)
 	}
 
-	// Check if it's the current version
+	// If it's the current version, switch away before removing it so the
+	// shell is never left without a Go toolchain
 	current, _ := m.installer.GetCurrent()
 	if current == version {
-		ui.PrintWarning("Removing current version, you may need to switch to another version")
+		if fallback := m.fallbackFor(version, batch); fallback != "" {
+			if err := m.installer.SetCurrent(fallback); err != nil {
+				return fmt.Errorf("failed to switch to Go %s: %w", fallback, err)
+			}
+			ui.PrintInfo("Switched current version to Go %s", fallback)
+		} else {
+			ui.PrintWarning("Removing the last installed version, no Go version will be active")
+		}
 	}
 
 	if err := m.installer.Uninstall(version); err != nil {
@@ -154,11 +208,16 @@ func (m *Manager) Uninstall(version string) error {
 	}
 
 	ui.PrintSuccess("Uninstalled Go %s", version)
+
+	if err := m.Rehash(); err != nil {
+		ui.PrintWarning("Failed to update shims: %s", err)
+	}
 	return nil
 }
 
-// Use switches to a specific Go version
-func (m *Manager) Use(version string) error {
+// EnsureInstalled resolves a version or alias to a full version and installs
+// it if it is missing and auto-install is enabled
+func (m *Manager) EnsureInstalled(version string) (string, error) {
 	// Resolve alias if needed
 	version = config.ResolveVersion(version)
 
@@ -167,15 +226,26 @@ func (m *Manager) Use(version string) error {
 
 	if !m.installer.IsInstalled(version) {
 		// Check if auto-install is enabled
-		cfg := config.Get()
-		if cfg.AutoInstall {
-			ui.PrintInfo("Version %s not installed, installing...", version)
-			if err := m.Install(version, false, true); err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("version %s is not installed (auto-install is disabled)", version)
+		if GoToolchainFromEnv().NoDownload {
+			return "", fmt.Errorf("version %s is not installed (GOTOOLCHAIN=%s does not allow downloads)", version, os.Getenv(EnvGoToolchain))
 		}
+		if !AutoInstallAllowed() {
+			return "", fmt.Errorf("version %s is not installed (auto-install is disabled)", version)
+		}
+		ui.PrintInfo("Version %s not installed, installing...", version)
+		if err := m.Install(version, false, true); err != nil {
+			return "", err
+		}
+	}
+
+	return version, nil
+}
+
+// Use switches to a specific Go version
+func (m *Manager) Use(version string) error {
+	version, err := m.EnsureInstalled(version)
+	if err != nil {
+		return err
 	}
 
 	if err := m.installer.SetCurrent(version); err != nil {
@@ -193,14 +263,9 @@ func (m *Manager) UseFromProject(dir string) error {
 		return err
 	}
 
-	// Normalize to full version (e.g., 1.21 -> 1.21.0)
-	fullVersion, err := NormalizeDetectedVersion(version)
-	if err != nil {
-		fullVersion = version
-	}
-
-	ui.PrintInfo("Detected Go %s from %s", fullVersion, source)
-	return m.Use(fullVersion)
+	// Use resolves partial versions against installed versions first
+	ui.PrintInfo("Detected Go %s from %s", version, source)
+	return m.Use(version)
 }
 
 // Current returns the current Go version
@@ -213,12 +278,33 @@ func (m *Manager) ListInstalled() ([]string, error) {
 	return m.installer.ListInstalled()
 }
 
+// ResolveInstalled returns the installed versions matching a version, alias,
+// partial version or constraint expression (newest first)
+func (m *Manager) ResolveInstalled(spec string) ([]string, error) {
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	spec = config.ResolveVersion(spec)
+	matched, err := MatchVersions(spec, installed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version or constraint %q: %w", spec, err)
+	}
+	return matched, nil
+}
+
 // IsInstalled checks if a version is installed
 func (m *Manager) IsInstalled(version string) bool {
 	version = config.NormalizeVersion(version)
 	return m.installer.IsInstalled(version)
 }
 
+// GoRoot returns the GOROOT directory of an installed version
+func (m *Manager) GoRoot(version string) string {
+	return filepath.Join(m.paths.VersionPath(version), "go")
+}
+
 // GetGoBinary returns the path to the Go binary for a version
 func (m *Manager) GetGoBinary(version string) (string, error) {
 	version = config.ResolveVersion(version)
@@ -254,8 +340,7 @@ func (m *Manager) QuietUse(version string) error {
 	version = m.resolveFullVersion(version)
 
 	if !m.installer.IsInstalled(version) {
-		cfg := config.Get()
-		if cfg.AutoInstall {
+		if AutoInstallAllowed() {
 			// Install quietly
 			archivePath, err := m.downloader.Download(version, false)
 			if err != nil {
@@ -264,6 +349,7 @@ func (m *Manager) QuietUse(version string) error {
 			if err := m.installer.Install(archivePath, version); err != nil {
 				return err
 			}
+			m.Rehash()
 		} else {
 			return fmt.Errorf("version %s is not installed", version)
 		}
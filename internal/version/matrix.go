@@ -0,0 +1,166 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// Keywords accepted by ExpandMatrix next to versions and constraints
+const (
+	// MatrixSupported stands for the two newest minor lines, the releases
+	// the Go release policy supports
+	MatrixSupported = "supported"
+	// MatrixGoMod stands for every minor line from the go directive of the
+	// nearest go.mod through the latest release
+	MatrixGoMod = "go.mod"
+)
+
+// ExpandMatrix turns matrix specs (versions, partial versions, constraints,
+// aliases and the Matrix* keywords) into one version per matching minor
+// line: the newest installed patch of the line, or its newest release when
+// the line is not installed. The result is sorted oldest first.
+func (m *Manager) ExpandMatrix(specs []string) ([]string, error) {
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	// Releases are only fetched when a spec needs them
+	var releases []string
+	var releasesErr error
+	fetched := false
+	remote := func() ([]string, error) {
+		if !fetched {
+			releases, releasesErr = ListStableVersions()
+			fetched = true
+		}
+		return releases, releasesErr
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+
+	for _, spec := range specs {
+		// Keywords are matched as written: normalizing would turn "go.mod"
+		// into ".mod"
+		switch spec {
+		case MatrixSupported:
+			stable, err := remote()
+			if err != nil {
+				return nil, fmt.Errorf("failed to list supported releases: %w", err)
+			}
+			if spec = supportedConstraint(stable); spec == "" {
+				return nil, fmt.Errorf("no stable releases found")
+			}
+		case MatrixGoMod:
+			minimum, path, err := ModuleMinimum("")
+			if err != nil {
+				return nil, err
+			}
+			if minimum == "" {
+				return nil, fmt.Errorf("%s has no go directive", path)
+			}
+			spec = ">=" + MinorLine(minimum)
+		default:
+			spec = config.ResolveVersion(spec)
+		}
+
+		lines := make(map[string]string)
+		matched, err := MatchVersions(spec, installed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version or constraint %q: %w", spec, err)
+		}
+		addNewestPerLine(lines, matched)
+
+		// Constraints may span lines that are not installed; versions only
+		// need the release index when nothing installed matches
+		if len(matched) == 0 || IsConstraint(spec) {
+			if stable, err := remote(); err == nil {
+				found, _ := MatchVersions(spec, stable)
+				addNewestPerLine(lines, found)
+			} else if len(lines) == 0 {
+				return nil, fmt.Errorf("no installed version matches %s and the release index is unavailable: %w", spec, err)
+			}
+		}
+
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("no Go release matches %s", spec)
+		}
+		for _, v := range lines {
+			if !seen[v] {
+				seen[v] = true
+				result = append(result, v)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return compareVersions(result[i], result[j]) < 0 })
+	return result, nil
+}
+
+// ModuleMinimum returns the go directive of the nearest go.mod in dir or
+// its parents, and the file's path. Parents are searched like Resolve does:
+// up to the configured boundaries, and not at all when inherit is off.
+func ModuleMinimum(dir string) (string, string, error) {
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return "", "", err
+		}
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	cfg := config.Get()
+	dirs := searchDirs(dir, cfg)
+	if !cfg.InheritVersion {
+		dirs = dirs[:1]
+	}
+	for _, d := range dirs {
+		path := filepath.Join(d, GoModFile)
+		if _, err := os.Stat(path); err == nil {
+			directives, err := parseGoDirectives(path)
+			if err != nil {
+				return "", path, err
+			}
+			return directives.Go, path, nil
+		}
+	}
+	return "", "", fmt.Errorf("no go.mod found")
+}
+
+// addNewestPerLine records the newest of versions (sorted newest first) for
+// every minor line that has no version yet
+func addNewestPerLine(lines map[string]string, versions []string) {
+	for _, v := range versions {
+		line := MinorLine(v)
+		if _, ok := lines[line]; !ok && line != "" {
+			lines[line] = v
+		}
+	}
+}
+
+// supportedConstraint returns a constraint matching the two newest minor
+// lines of stable (sorted newest first), or "" when stable is empty
+func supportedConstraint(stable []string) string {
+	var lines []string
+	for _, v := range stable {
+		line := MinorLine(v)
+		if len(lines) == 0 || lines[len(lines)-1] != line {
+			lines = append(lines, line)
+		}
+		if len(lines) == 2 {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return ">=" + lines[len(lines)-1]
+}
@@ -0,0 +1,121 @@
+package version
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	goversion "github.com/hashicorp/go-version"
+)
+
+const goReleaseHistoryURL = "https://go.dev/doc/devel/release"
+
+// securityReleaseRegex matches release history entries such as
+// "go1.22.5 (released 2024-07-02) includes security fixes to ..."
+var securityReleaseRegex = regexp.MustCompile(`go(\d+\.\d+(?:\.\d+)?)\s*\(released [^)]*\)\s*includes\s+(?:a\s+)?security\s+fix`)
+
+// Outdated is an installed minor line with a newer patch release available
+type Outdated struct {
+	Minor      string   // Minor line, e.g. "1.22"
+	Installed  string   // Newest installed version of the line
+	Latest     string   // Newest available stable version of the line
+	Security   []string // Releases after Installed up to Latest that include security fixes
+	Superseded []string // Installed versions of the line older than Latest, newest first
+}
+
+// ListOutdated returns every installed minor line whose newest installed
+// version is behind the newest stable release of that line. Security
+// releases are marked on a best-effort basis from the go.dev release history.
+func (m *Manager) ListOutdated() ([]Outdated, error) {
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	stable, err := ListStableVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	security, _ := FetchSecurityReleases()
+
+	// installed and stable are sorted newest first, so the first version
+	// seen for each minor line is the newest
+	var result []Outdated
+	seen := make(map[string]bool)
+	for _, ver := range installed {
+		minor := MinorLine(ver)
+		if minor == "" || seen[minor] {
+			continue
+		}
+		seen[minor] = true
+
+		latest := ""
+		for _, v := range stable {
+			if MinorLine(v) == minor {
+				latest = v
+				break
+			}
+		}
+		if latest == "" || compareVersions(latest, ver) <= 0 {
+			continue
+		}
+
+		o := Outdated{Minor: minor, Installed: ver, Latest: latest}
+		for _, v := range stable {
+			if MinorLine(v) == minor && compareVersions(v, ver) > 0 && security[v] {
+				o.Security = append(o.Security, v)
+			}
+		}
+		for _, v := range installed {
+			if MinorLine(v) == minor && compareVersions(v, latest) < 0 {
+				o.Superseded = append(o.Superseded, v)
+			}
+		}
+		result = append(result, o)
+	}
+
+	return result, nil
+}
+
+// FetchSecurityReleases returns the set of Go releases whose release notes
+// mention security fixes
+func FetchSecurityReleases() (map[string]bool, error) {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
+	resp, err := client.Get(goReleaseHistoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release history: %w", err)
+	}
+
+	releases := make(map[string]bool)
+	for _, match := range securityReleaseRegex.FindAllSubmatch(body, -1) {
+		releases[string(match[1])] = true
+	}
+	return releases, nil
+}
+
+// MinorLine returns the "X.Y" release line of a version, including for
+// pre-releases ("1.23rc1" -> "1.23"), or "" if the version does not parse
+func MinorLine(version string) string {
+	v, err := goversion.NewVersion(version)
+	if err != nil {
+		return ""
+	}
+	segments := v.Segments()
+	return fmt.Sprintf("%d.%d", segments[0], segments[1])
+}
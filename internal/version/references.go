@@ -0,0 +1,130 @@
+package version
+
+import (
+	"sort"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// Reference kinds
+const (
+	RefAlias   = "alias"
+	RefDefault = "default_version"
+)
+
+// Reference is a config setting that points at a specific Go version
+type Reference struct {
+	Kind string // RefAlias or RefDefault
+	Name string // Alias name (empty for default_version)
+}
+
+// String returns a human-readable description of the reference
+func (r Reference) String() string {
+	if r.Kind == RefAlias {
+		return "alias '" + r.Name + "'"
+	}
+	return r.Kind
+}
+
+// FindReferences returns the aliases and default_version that point at version
+func (m *Manager) FindReferences(version string) []Reference {
+	cfg := config.Get()
+
+	var refs []Reference
+	var names []string
+	for name, target := range cfg.Aliases {
+		if target == version {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		refs = append(refs, Reference{Kind: RefAlias, Name: name})
+	}
+
+	if cfg.DefaultVersion == version {
+		refs = append(refs, Reference{Kind: RefDefault})
+	}
+
+	return refs
+}
+
+// RepointReferences points every reference at a new version
+func (m *Manager) RepointReferences(refs []Reference, version string) error {
+	cfg := config.Get()
+	for _, ref := range refs {
+		switch ref.Kind {
+		case RefAlias:
+			cfg.Aliases[ref.Name] = version
+		case RefDefault:
+			cfg.DefaultVersion = version
+		}
+	}
+	return config.Save(cfg)
+}
+
+// RemoveReferences deletes aliases and clears default_version for every reference
+func (m *Manager) RemoveReferences(refs []Reference) error {
+	cfg := config.Get()
+	for _, ref := range refs {
+		switch ref.Kind {
+		case RefAlias:
+			delete(cfg.Aliases, ref.Name)
+		case RefDefault:
+			cfg.DefaultVersion = ""
+		}
+	}
+	return config.Save(cfg)
+}
+
+// Replacement returns the installed version that best replaces version:
+// the newest install of the same minor line, otherwise the newest install.
+// Versions in exclude, such as others removed in the same batch, are never
+// chosen. Returns "" if nothing else is installed.
+func (m *Manager) Replacement(version string, exclude map[string]bool) string {
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return ""
+	}
+
+	var remaining []string
+	for _, v := range installed {
+		if v != version && !exclude[v] {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) == 0 {
+		return ""
+	}
+
+	if sameMinor := matchPrefix(MinorLine(version), remaining); len(sameMinor) > 0 {
+		return sameMinor[0]
+	}
+	return remaining[0]
+}
+
+// fallbackFor returns the version to activate when version is removed while
+// current: default_version if it is still installed, else the newest install.
+// Versions in exclude are never chosen.
+func (m *Manager) fallbackFor(version string, exclude map[string]bool) string {
+	if def := config.ResolveVersion(m.GetDefault()); def != "" && def != version {
+		if matched, err := m.ResolveInstalled(def); err == nil {
+			for _, v := range matched {
+				if v != version && !exclude[v] {
+					return v
+				}
+			}
+		}
+	}
+
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return ""
+	}
+	for _, v := range installed {
+		if v != version && !exclude[v] {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReleasesBetween returns the stable releases from the release index that
+// are newer than good and older than bad, oldest first, followed by bad
+func ReleasesBetween(good, bad string) ([]string, error) {
+	if compareVersions(good, bad) >= 0 {
+		return nil, fmt.Errorf("good version %s must be older than bad version %s", good, bad)
+	}
+
+	stable, err := ListStableVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []string
+	for _, v := range stable {
+		if compareVersions(v, good) > 0 && compareVersions(v, bad) < 0 {
+			releases = append(releases, v)
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool { return compareVersions(releases[i], releases[j]) < 0 })
+	return append(releases, bad), nil
+}
+
+// ReleaseNotesURL returns the release notes of a version: the major release
+// notes for X.Y and X.Y.0, the release history entry for a patch release
+func ReleaseNotesURL(version string) string {
+	line := MinorLine(version)
+	if line == "" {
+		return goReleaseHistoryURL
+	}
+	if version == line || version == line+".0" {
+		return "https://go.dev/doc/go" + line
+	}
+	return goReleaseHistoryURL + "#go" + version
+}
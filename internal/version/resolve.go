@@ -0,0 +1,52 @@
+package version
+
+import (
+	"fmt"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// Sources a version can be resolved from, in precedence order
+const (
+	SourceSession = "session" // GOVM_SHELL_VERSION, or GOVM_AUTO_VERSION below project files
+	SourceProject = "project" // go.work / go.mod
+	SourceDefault = "default" // default_version in config.toml
+	SourceGlobal  = "global"  // ~/.govm/current symlink
+)
+
+// Resolution describes which Go version applies to a directory and why
+type Resolution struct {
+	Source  string // One of the Source* constants
+	Origin  string // File path or setting the value was read from
+	Raw     string // Value as written at the origin
+	Version string // Installed version that satisfies Raw ("" if none)
+}
+
+// Resolve determines the Go version for dir from the 'govm shell' session,
+// project files, the auto-switch session, default_version and finally the
+// global symlink.
+// It only looks at installed versions and never touches the network, so
+// it is cheap enough to run on every shim invocation.
+func Resolve(dir string) (*Resolution, error) {
+	candidates, err := Explain(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		if c.Selected {
+			return &Resolution{Source: c.Source, Origin: c.Origin, Raw: c.Raw, Version: c.Version}, nil
+		}
+	}
+	return nil, fmt.Errorf("no Go version is active (run 'govm use <version>')")
+}
+
+// SelectInstalled returns the newest installed version satisfying a version,
+// alias, partial version or constraint, or "" if none does
+func SelectInstalled(spec string, installed []string) string {
+	matched, err := MatchVersions(config.ResolveVersion(spec), installed)
+	if err != nil || len(matched) == 0 {
+		return ""
+	}
+	return matched[0]
+}
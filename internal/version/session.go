@@ -0,0 +1,19 @@
+package version
+
+import (
+	"os"
+
+	"github.com/wenzzy/govm/internal/config"
+)
+
+// SessionVersion returns the version activated for the current shell only,
+// and the environment variable it was read from. A version set with
+// 'govm shell' takes precedence over one set by the session-scope hook.
+func SessionVersion() (string, string) {
+	for _, key := range []string{config.EnvShellVersion, config.EnvAutoVersion} {
+		if v := os.Getenv(key); v != "" {
+			return v, key
+		}
+	}
+	return "", ""
+}
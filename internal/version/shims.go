@@ -0,0 +1,107 @@
+package version
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// reservedBinNames are the govm entry points in Paths.Bin, never treated as shims
+var reservedBinNames = map[string]bool{"govm": true, "g": true}
+
+// defaultShims are created even before any toolchain is installed
+var defaultShims = []string{"go", "gofmt"}
+
+// Rehash creates a shim in Paths.Bin for every tool found in the bin directory
+// of an installed toolchain and removes shims for tools that no longer exist.
+// A shim is a symlink to the govm binary, which detects the name it was
+// invoked as and runs that tool from the resolved toolchain.
+func (m *Manager) Rehash() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate govm binary: %w", err)
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return fmt.Errorf("failed to locate govm binary: %w", err)
+	}
+
+	// Keep the link relative when govm itself lives in Paths.Bin
+	target := exe
+	if filepath.Dir(exe) == m.paths.Bin {
+		target = filepath.Base(exe)
+	}
+
+	names := make(map[string]bool)
+	for _, name := range defaultShims {
+		names[name] = true
+	}
+	installed, err := m.installer.ListInstalled()
+	if err != nil {
+		return err
+	}
+	for _, ver := range installed {
+		entries, err := os.ReadDir(m.paths.VersionBinPath(ver))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && !reservedBinNames[entry.Name()] {
+				names[entry.Name()] = true
+			}
+		}
+	}
+
+	// Remove stale shims
+	entries, err := os.ReadDir(m.paths.Bin)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if names[name] || reservedBinNames[name] {
+			continue
+		}
+		if isShim(filepath.Join(m.paths.Bin, name), exe) {
+			os.Remove(filepath.Join(m.paths.Bin, name))
+		}
+	}
+
+	// Create missing shims
+	for name := range names {
+		link := filepath.Join(m.paths.Bin, name)
+		if existing, err := os.Readlink(link); err == nil && existing == target {
+			continue
+		}
+		if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			// Never replace a real file the user put there
+			continue
+		}
+
+		tmpLink := filepath.Join(m.paths.Bin, fmt.Sprintf(".%s.%d", name, os.Getpid()))
+		os.Remove(tmpLink)
+		if err := os.Symlink(target, tmpLink); err != nil {
+			return fmt.Errorf("failed to create shim %s: %w", name, err)
+		}
+		if err := os.Rename(tmpLink, link); err != nil {
+			os.Remove(tmpLink)
+			return fmt.Errorf("failed to create shim %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isShim reports whether path is a symlink that resolves to the govm binary
+func isShim(path, exe string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		// Dangling links to an old govm location are stale shims too
+		return true
+	}
+	return resolved == exe
+}
@@ -0,0 +1,77 @@
+package version
+
+import (
+	"os"
+	"strings"
+
+	goversion "github.com/hashicorp/go-version"
+)
+
+// EnvGoToolchain is the environment variable Go uses to select a toolchain
+const EnvGoToolchain = "GOTOOLCHAIN"
+
+// GoToolchain is a parsed GOTOOLCHAIN value
+type GoToolchain struct {
+	Version    string // Version named by goX.Y.Z, "" for local/auto/path
+	Minimum    bool   // Version is a minimum (goX.Y.Z+auto, goX.Y.Z+path) rather than a pin
+	NoDownload bool   // "path" mode: only use toolchains that are already installed
+}
+
+// ParseGoToolchain parses a GOTOOLCHAIN value:
+//
+//	local, auto, path          project files decide
+//	goX.Y.Z                    always use X.Y.Z
+//	goX.Y.Z+auto, goX.Y.Z+path use at least X.Y.Z, newer if the project requires it
+//
+// The "path" forms never install missing versions.
+func ParseGoToolchain(value string) GoToolchain {
+	value = strings.TrimSpace(value)
+	name, mode, hasMode := strings.Cut(value, "+")
+
+	tc := GoToolchain{
+		NoDownload: mode == "path" || value == "path",
+	}
+
+	if strings.HasPrefix(name, "go") {
+		tc.Version = strings.TrimPrefix(name, "go")
+		// Strip custom suffixes like go1.22.1-boringcrypto
+		tc.Version, _, _ = strings.Cut(tc.Version, "-")
+		tc.Minimum = hasMode
+	}
+	return tc
+}
+
+// GoToolchainFromEnv parses the GOTOOLCHAIN environment variable
+func GoToolchainFromEnv() GoToolchain {
+	return ParseGoToolchain(os.Getenv(EnvGoToolchain))
+}
+
+// applyGoToolchainEnv adjusts a detected project version for GOTOOLCHAIN
+func applyGoToolchainEnv(version, source string, err error) (string, string, error) {
+	tc := GoToolchainFromEnv()
+	if tc.Version == "" {
+		return version, source, err
+	}
+
+	if !tc.Minimum || err != nil || version == "" || compareVersions(tc.Version, version) > 0 {
+		return tc.Version, EnvGoToolchain, nil
+	}
+	return version, source, err
+}
+
+// CompareVersions compares two Go versions in release order (1.22rc1 sorts
+// before 1.22.0), returning -1, 0 or 1
+func CompareVersions(a, b string) int {
+	return compareVersions(a, b)
+}
+
+// compareVersions compares two Go versions, returning -1, 0 or 1.
+// Unparseable versions fall back to string comparison.
+func compareVersions(a, b string) int {
+	va, errA := goversion.NewVersion(a)
+	vb, errB := goversion.NewVersion(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return va.Compare(vb)
+}
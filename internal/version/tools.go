@@ -0,0 +1,215 @@
+package version
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wenzzy/govm/internal/config"
+	"github.com/wenzzy/govm/internal/ui"
+)
+
+// Tool is a command installed with 'go install'
+type Tool struct {
+	Path    string // Package path, e.g. golang.org/x/tools/gopls
+	Version string // Version or constraint from the manifest, or the installed version
+}
+
+func (t Tool) String() string {
+	return t.Path + "@" + toolQuery(t.Version)
+}
+
+// ToolResult is the outcome of installing a single tool
+type ToolResult struct {
+	Tool Tool
+	Err  error
+}
+
+// ManifestTools returns the [tools] manifest of config.toml, sorted by path
+func ManifestTools() []Tool {
+	var tools []Tool
+	for path, ver := range config.Get().Tools {
+		tools = append(tools, Tool{Path: path, Version: ver})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Path < tools[j].Path })
+	return tools
+}
+
+// InstallTools runs 'go install' for every tool with the given Go version,
+// reporting each one. A failing tool does not stop the others; the results
+// tell the caller which ones failed.
+func (m *Manager) InstallTools(version string, tools []Tool) []ToolResult {
+	goBin, err := m.GetGoBinary(version)
+	if err == nil && ToolEnviron == nil {
+		err = fmt.Errorf("no environment to install tools in")
+	}
+	var environ []string
+	if err == nil {
+		environ, err = ToolEnviron(version)
+	}
+	results := make([]ToolResult, 0, len(tools))
+	if err != nil {
+		for _, t := range tools {
+			results = append(results, ToolResult{Tool: t, Err: err})
+		}
+		return results
+	}
+
+	for _, t := range tools {
+		spinner := ui.NewSpinner(fmt.Sprintf("Installing %s...", t))
+		spinner.Start()
+
+		cmd := exec.Command(goBin, "install", t.String())
+		cmd.Env = environ
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			spinner.Fail(fmt.Sprintf("Failed to install %s", t))
+			if msg := lastLine(string(out)); msg != "" {
+				err = fmt.Errorf("%s", msg)
+			}
+			ui.PrintHint("%s", err)
+		} else {
+			spinner.Success(fmt.Sprintf("Installed %s", t))
+		}
+		results = append(results, ToolResult{Tool: t, Err: err})
+	}
+	return results
+}
+
+// installManifestTools installs the [tools] manifest into a freshly
+// installed toolchain. Failures are reported but never fail the install.
+func (m *Manager) installManifestTools(version string) {
+	tools := ManifestTools()
+	if len(tools) == 0 {
+		return
+	}
+
+	ui.PrintInfo("Installing %d tool(s) for Go %s...", len(tools), version)
+	if failed := FailedTools(m.InstallTools(version, tools)); len(failed) > 0 {
+		ui.PrintWarning("%d of %d tool(s) failed to install", len(failed), len(tools))
+		ui.PrintHint("Retry with: govm tools sync %s", version)
+	}
+}
+
+// FailedTools returns the results that failed
+func FailedTools(results []ToolResult) []ToolResult {
+	var failed []ToolResult
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// InstalledTools returns the tools built by the given Go version, read
+// from the build information of the binaries in its tools directory
+func (m *Manager) InstalledTools(version string) ([]Tool, error) {
+	dir, isolated := m.ToolsDir(version)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var tools []Tool
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := buildinfo.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		// A shared directory holds tools built by every version
+		if built := strings.Fields(info.GoVersion); !isolated && (len(built) == 0 || built[0] != "go"+version) {
+			continue
+		}
+
+		ver := info.Main.Version
+		if ver == "" || ver == "(devel)" {
+			ver = "latest"
+		}
+		tools = append(tools, Tool{Path: info.Path, Version: ver})
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Path < tools[j].Path })
+	return tools, nil
+}
+
+// ToolsDir returns the directory 'go install' puts the tools of version in,
+// and whether that directory belongs to the version alone (isolate gobin or
+// gopath)
+func (m *Manager) ToolsDir(version string) (string, bool) {
+	if _, toolsBin := m.paths.Isolation(config.Get(), version); toolsBin != "" {
+		return toolsBin, true
+	}
+	if gobin := os.Getenv("GOBIN"); gobin != "" && !m.paths.IsIsolated(gobin) {
+		return gobin, false
+	}
+	return filepath.Join(m.paths.SharedGopath(), "bin"), false
+}
+
+// ToolEnviron returns the environment 'go install' runs in for a version:
+// the environment that activates the version, without toolchain switching.
+// The shell package owns that environment and imports this one, so it sets
+// ToolEnviron when it is linked in.
+var ToolEnviron func(version string) ([]string, error)
+
+// toolQuery converts a manifest version to a 'go install' version query.
+// Empty means latest, 0.16.2 becomes v0.16.2, ~0.16 and ^0.16 the newest
+// v0.16.x, ^1.2 the newest v1.x.y, and comparisons such as >=1.2 keep their
+// operator. Anything else (branches, commits, v-prefixed versions) is passed
+// through unchanged.
+func toolQuery(spec string) string {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return "latest"
+	case strings.HasPrefix(spec, "~"):
+		return semverPrefix(spec[1:], 2)
+	case strings.HasPrefix(spec, "^"):
+		// Caret keeps the first non-zero segment, as in npm and Cargo
+		if strings.HasPrefix(strings.TrimPrefix(spec[1:], "v"), "0.") {
+			return semverPrefix(spec[1:], 2)
+		}
+		return semverPrefix(spec[1:], 1)
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(spec, op) {
+			return op + withV(strings.TrimSpace(spec[len(op):]))
+		}
+	}
+	return withV(spec)
+}
+
+// semverPrefix returns the first n segments of a version as a v-prefixed
+// prefix query
+func semverPrefix(ver string, n int) string {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(ver), "v"), ".")
+	if len(parts) > n {
+		parts = parts[:n]
+	}
+	return "v" + strings.Join(parts, ".")
+}
+
+// withV adds the v prefix module versions need to a bare version number
+func withV(ver string) string {
+	if ver != "" && ver[0] >= '0' && ver[0] <= '9' {
+		return "v" + ver
+	}
+	return ver
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}